@@ -1,12 +1,14 @@
 package pi
 
 import (
+	"context"
 	"os"
 
 	"github.com/joshp123/pi-golang/internal/sdk"
 )
 
-// Debug enables verbose logging when PI_DEBUG=1.
+// Debug turns on debug-level logging to stderr for clients started without
+// Options.Logger (default: PI_DEBUG=1). Read once per client start.
 var Debug = os.Getenv("PI_DEBUG") == "1"
 
 func init() {
@@ -28,3 +30,32 @@ func StartOneShot(options OneShotOptions) (*OneShotClient, error) {
 	sdk.DefaultEnvAllowPrefixes = DefaultEnvAllowPrefixes
 	return sdk.StartOneShot(options)
 }
+
+type Pool = sdk.Pool
+type PoolOptions = sdk.PoolOptions
+type PoolStats = sdk.PoolStats
+
+func NewPool(options OneShotOptions, poolOptions PoolOptions) (*Pool, error) {
+	sdk.DefaultEnvAllowlist = DefaultEnvAllowlist
+	sdk.DefaultEnvAllowPrefixes = DefaultEnvAllowPrefixes
+	return sdk.NewPool(options, poolOptions)
+}
+
+type Supervisor = sdk.Supervisor
+type SupervisorOptions = sdk.SupervisorOptions
+type SupervisorStats = sdk.SupervisorStats
+
+func StartSupervisor(options SessionOptions, supervisorOptions SupervisorOptions) (*Supervisor, error) {
+	sdk.DefaultEnvAllowlist = DefaultEnvAllowlist
+	sdk.DefaultEnvAllowPrefixes = DefaultEnvAllowPrefixes
+	return sdk.StartSupervisor(options, supervisorOptions)
+}
+
+type DetailedRunner = sdk.DetailedRunner
+type JSONOptions = sdk.JSONOptions
+type JSONResult[T any] = sdk.JSONResult[T]
+
+// RunJSON prompts for a JSON value matching T's derived schema, repairing invalid replies.
+func RunJSON[T any](ctx context.Context, runner DetailedRunner, request PromptRequest, options JSONOptions) (JSONResult[T], error) {
+	return sdk.RunJSON[T](ctx, runner, request, options)
+}
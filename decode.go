@@ -6,10 +6,38 @@ import (
 	"github.com/joshp123/pi-golang/internal/sdk"
 )
 
+func DecodeEvent(event Event) (TypedEvent, error) {
+	return sdk.DecodeEvent(event)
+}
+
+func DecodeContentBlocks(content json.RawMessage) ([]ContentBlock, error) {
+	return sdk.DecodeContentBlocks(content)
+}
+
+func DecodeAgentStart(raw json.RawMessage) (AgentStartEvent, error) {
+	return sdk.DecodeAgentStart(raw)
+}
+
 func DecodeAgentEnd(raw json.RawMessage) (AgentEndEvent, error) {
 	return sdk.DecodeAgentEnd(raw)
 }
 
+func DecodeTurnStart(raw json.RawMessage) (TurnStartEvent, error) {
+	return sdk.DecodeTurnStart(raw)
+}
+
+func DecodeTurnEnd(raw json.RawMessage) (TurnEndEvent, error) {
+	return sdk.DecodeTurnEnd(raw)
+}
+
+func DecodeMessageStart(raw json.RawMessage) (MessageStartEvent, error) {
+	return sdk.DecodeMessageStart(raw)
+}
+
+func DecodeMessageEnd(raw json.RawMessage) (MessageEndEvent, error) {
+	return sdk.DecodeMessageEnd(raw)
+}
+
 func DecodeMessageUpdate(raw json.RawMessage) (MessageUpdateEvent, error) {
 	return sdk.DecodeMessageUpdate(raw)
 }
@@ -30,6 +58,26 @@ func DecodeAutoRetryEnd(raw json.RawMessage) (AutoRetryEndEvent, error) {
 	return sdk.DecodeAutoRetryEnd(raw)
 }
 
+func DecodeToolExecutionStart(raw json.RawMessage) (ToolExecutionStartEvent, error) {
+	return sdk.DecodeToolExecutionStart(raw)
+}
+
+func DecodeToolExecutionUpdate(raw json.RawMessage) (ToolExecutionUpdateEvent, error) {
+	return sdk.DecodeToolExecutionUpdate(raw)
+}
+
+func DecodeToolExecutionEnd(raw json.RawMessage) (ToolExecutionEndEvent, error) {
+	return sdk.DecodeToolExecutionEnd(raw)
+}
+
 func DecodeTerminalOutcome(raw json.RawMessage) (TerminalOutcome, error) {
 	return sdk.DecodeTerminalOutcome(raw)
 }
+
+func DecodeExtensionUIRequest(raw json.RawMessage) (ExtensionUIRequest, error) {
+	return sdk.DecodeExtensionUIRequest(raw)
+}
+
+func DecodeToolCallDenied(raw json.RawMessage) (ToolCallDeniedEvent, error) {
+	return sdk.DecodeToolCallDenied(raw)
+}
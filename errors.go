@@ -9,7 +9,14 @@ var (
 	ErrNilContext                = sdk.ErrNilContext
 	ErrRunInProgress             = sdk.ErrRunInProgress
 	ErrInvalidSubscriptionPolicy = sdk.ErrInvalidSubscriptionPolicy
+	ErrInvalidStructuredOutput   = sdk.ErrInvalidStructuredOutput
+	ErrBudgetExceeded            = sdk.ErrBudgetExceeded
+	ErrRunStalled                = sdk.ErrRunStalled
+	ErrCrashLoop                 = sdk.ErrCrashLoop
 )
 
 type RPCError = sdk.RPCError
 type MissingProviderAuthError = sdk.MissingProviderAuthError
+type BudgetExceededError = sdk.BudgetExceededError
+type RunStalledError = sdk.RunStalledError
+type ProcessExitError = sdk.ProcessExitError
@@ -25,6 +25,21 @@ const (
 	CommandCompact     = "compact"
 	CommandExportHTML  = "export_html"
 	CommandGetCommands = "get_commands"
+
+	CommandSetModel           = "set_model"
+	CommandCycleModel         = "cycle_model"
+	CommandGetAvailableModels = "get_available_models"
+	CommandSetThinkingLevel   = "set_thinking_level"
+	CommandCycleThinkingLevel = "cycle_thinking_level"
+	CommandBash               = "bash"
+	CommandAbortBash          = "abort_bash"
+	CommandGetSessionStats    = "get_session_stats"
+	CommandSwitchSession      = "switch_session"
+	CommandFork               = "fork"
+	CommandGetForkMessages    = "get_fork_messages"
+	CommandGetMessages        = "get_messages"
+
+	CommandExtensionUIResponse = "extension_ui_response"
 )
 
 const (
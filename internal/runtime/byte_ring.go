@@ -0,0 +1,45 @@
+package runtime
+
+import "sync"
+
+// ByteRing keeps the most recent Limit bytes written to it. Storage grows
+// lazily up to Limit, then wraps.
+type ByteRing struct {
+	mu        sync.Mutex
+	data      []byte
+	start     int
+	limit     int
+	truncated bool
+}
+
+func NewByteRing(limit int) *ByteRing {
+	return &ByteRing{limit: max(limit, 1)}
+}
+
+func (ring *ByteRing) Write(chunk []byte) {
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	if free := ring.limit - len(ring.data); free > 0 {
+		take := min(free, len(chunk))
+		ring.data = append(ring.data, chunk[:take]...)
+		chunk = chunk[take:]
+	}
+	for len(chunk) > 0 {
+		ring.truncated = true
+		written := copy(ring.data[ring.start:], chunk)
+		ring.start = (ring.start + written) % ring.limit
+		chunk = chunk[written:]
+	}
+}
+
+// Bytes returns a copy of the retained bytes, oldest first, and whether older
+// bytes have been discarded.
+func (ring *ByteRing) Bytes() ([]byte, bool) {
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	out := make([]byte, 0, len(ring.data))
+	out = append(out, ring.data[ring.start:]...)
+	return append(out, ring.data[:ring.start]...), ring.truncated
+}
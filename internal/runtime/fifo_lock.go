@@ -0,0 +1,90 @@
+package runtime
+
+import (
+	"context"
+	"sync"
+)
+
+// FIFOLock is a mutex granted to waiters in arrival order.
+// Acquire honors context cancellation while queued.
+type FIFOLock struct {
+	mu      sync.Mutex
+	held    bool
+	waiters []chan struct{}
+}
+
+func NewFIFOLock() *FIFOLock {
+	return &FIFOLock{}
+}
+
+// TryAcquire takes the lock only if it is free and nobody is queued.
+func (lock *FIFOLock) TryAcquire() bool {
+	lock.mu.Lock()
+	defer lock.mu.Unlock()
+	if lock.held || len(lock.waiters) > 0 {
+		return false
+	}
+	lock.held = true
+	return true
+}
+
+func (lock *FIFOLock) Acquire(ctx context.Context) error {
+	lock.mu.Lock()
+	if !lock.held && len(lock.waiters) == 0 {
+		lock.held = true
+		lock.mu.Unlock()
+		return nil
+	}
+	granted := make(chan struct{})
+	lock.waiters = append(lock.waiters, granted)
+	lock.mu.Unlock()
+
+	select {
+	case <-granted:
+		return nil
+	case <-ctx.Done():
+	}
+
+	lock.mu.Lock()
+	defer lock.mu.Unlock()
+	for index, waiter := range lock.waiters {
+		if waiter == granted {
+			lock.waiters = append(lock.waiters[:index], lock.waiters[index+1:]...)
+			return ctx.Err()
+		}
+	}
+	// Ownership was handed over while we were cancelled: pass it on.
+	lock.releaseLocked()
+	return ctx.Err()
+}
+
+// Release hands the lock to the oldest waiter, or frees it.
+func (lock *FIFOLock) Release() {
+	lock.mu.Lock()
+	defer lock.mu.Unlock()
+	lock.releaseLocked()
+}
+
+func (lock *FIFOLock) releaseLocked() {
+	if len(lock.waiters) == 0 {
+		lock.held = false
+		return
+	}
+	next := lock.waiters[0]
+	lock.waiters[0] = nil
+	lock.waiters = lock.waiters[1:]
+	close(next)
+}
+
+// Waiting reports queued acquirers (excluding the holder).
+func (lock *FIFOLock) Waiting() int {
+	lock.mu.Lock()
+	defer lock.mu.Unlock()
+	return len(lock.waiters)
+}
+
+func (lock *FIFOLock) Held() bool {
+	lock.mu.Lock()
+	defer lock.mu.Unlock()
+	return lock.held
+}
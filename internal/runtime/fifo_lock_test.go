@@ -0,0 +1,78 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFIFOLockGrantsWaitersInArrivalOrder(t *testing.T) {
+	lock := NewFIFOLock()
+	if !lock.TryAcquire() {
+		t.Fatal("expected free lock")
+	}
+
+	order := make(chan int, 3)
+	for index := range 3 {
+		go func() {
+			if err := lock.Acquire(context.Background()); err != nil {
+				t.Errorf("Acquire %d failed: %v", index, err)
+				return
+			}
+			order <- index
+			lock.Release()
+		}()
+		waitForWaiting(t, lock, index+1)
+	}
+
+	if lock.TryAcquire() {
+		t.Fatal("TryAcquire must not jump the queue")
+	}
+	lock.Release()
+	for want := range 3 {
+		select {
+		case got := <-order:
+			if got != want {
+				t.Fatalf("waiter %d acquired before %d", got, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for queued waiter")
+		}
+	}
+}
+
+func TestFIFOLockCancelledWaiterLeavesQueue(t *testing.T) {
+	lock := NewFIFOLock()
+	if !lock.TryAcquire() {
+		t.Fatal("expected free lock")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	acquired := make(chan error, 1)
+	go func() { acquired <- lock.Acquire(ctx) }()
+	waitForWaiting(t, lock, 1)
+
+	cancel()
+	if err := <-acquired; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if lock.Waiting() != 0 {
+		t.Fatalf("expected empty queue, got %d", lock.Waiting())
+	}
+	lock.Release()
+	if lock.Held() {
+		t.Fatal("expected lock to be free after release")
+	}
+}
+
+func waitForWaiting(t *testing.T, lock *FIFOLock, want int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for lock.Waiting() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d waiters, got %d", want, lock.Waiting())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -1,12 +1,16 @@
 package runtime
 
-import "sync"
+import (
+	"slices"
+	"sync"
+)
 
 // PendingRegistry tracks in-flight request channels and terminal process state.
 // Generic over response payload type so root package can keep wire types private.
 type PendingRegistry[T any] struct {
 	mu         sync.Mutex
 	pending    map[string]chan T
+	order      []string
 	processErr error
 	closed     bool
 	closedErr  error
@@ -30,9 +34,20 @@ func (registry *PendingRegistry[T]) Register(requestID string, response chan T)
 		return registry.closedErr
 	}
 	registry.pending[requestID] = response
+	registry.order = append(registry.order, requestID)
 	return nil
 }
 
+// Latest returns the most recently registered request still pending ("" if none).
+func (registry *PendingRegistry[T]) Latest() string {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if len(registry.order) == 0 {
+		return ""
+	}
+	return registry.order[len(registry.order)-1]
+}
+
 func (registry *PendingRegistry[T]) Drop(requestID string) {
 	responseChan := registry.takePending(requestID)
 	if responseChan == nil {
@@ -78,6 +93,7 @@ func (registry *PendingRegistry[T]) takePending(requestID string) chan T {
 		return nil
 	}
 	delete(registry.pending, requestID)
+	registry.order = slices.DeleteFunc(registry.order, func(id string) bool { return id == requestID })
 	return responseChan
 }
 
@@ -97,5 +113,6 @@ func (registry *PendingRegistry[T]) failPending(err error, closed bool) []chan T
 		pending = append(pending, responseChan)
 		delete(registry.pending, requestID)
 	}
+	registry.order = nil
 	return pending
 }
@@ -1,6 +1,10 @@
 package sdk
 
-import "context"
+import (
+	"context"
+
+	"github.com/joshp123/pi-golang/internal/rpc"
+)
 
 // Thin RPC mirror layer.
 // Each method maps directly to one upstream RPC command.
@@ -32,7 +36,10 @@ func (client *Client) FollowUp(ctx context.Context, request PromptRequest) error
 	return err
 }
 
+// Abort also cancels outstanding ApproveToolCall decisions and bridged tool
+// calls, which pi is blocked on.
 func (client *Client) Abort(ctx context.Context) error {
+	client.hostCalls.cancelAll()
 	_, err := client.send(ctx, abortCommand())
 	return err
 }
@@ -50,7 +57,11 @@ func (client *Client) NewSession(ctx context.Context, parentSession string) (boo
 	if err != nil {
 		return false, err
 	}
-	return decodeNewSessionCancelled(response.Data)
+	cancelled, err := decodeCancelled(rpc.CommandNewSession, response.Data)
+	if err == nil && !cancelled {
+		client.noteSessionChange()
+	}
+	return cancelled, err
 }
 
 func (client *Client) Compact(ctx context.Context, customInstructions string) (CompactResult, error) {
@@ -61,6 +72,89 @@ func (client *Client) Compact(ctx context.Context, customInstructions string) (C
 	return decodeCompactResult(response.Data)
 }
 
+func (client *Client) SetModel(ctx context.Context, provider string, modelID string) (ModelInfo, error) {
+	command, err := setModelCommand(provider, modelID)
+	if err != nil {
+		return ModelInfo{}, err
+	}
+	response, err := client.send(ctx, command)
+	if err != nil {
+		return ModelInfo{}, err
+	}
+	return decodeModelInfo(response.Data)
+}
+
+func (client *Client) CycleModel(ctx context.Context) (ModelCycleResult, error) {
+	response, err := client.send(ctx, cycleModelCommand())
+	if err != nil {
+		return ModelCycleResult{}, err
+	}
+	return decodeModelCycle(response.Data)
+}
+
+func (client *Client) GetAvailableModels(ctx context.Context) ([]ModelInfo, error) {
+	response, err := client.send(ctx, getAvailableModelsCommand())
+	if err != nil {
+		return nil, err
+	}
+	return decodeAvailableModels(response.Data)
+}
+
+func (client *Client) SetThinkingLevel(ctx context.Context, level ThinkingLevel) error {
+	command, err := setThinkingLevelCommand(level)
+	if err != nil {
+		return err
+	}
+	_, err = client.send(ctx, command)
+	return err
+}
+
+// CycleThinkingLevel returns the new level, or "" when the current model does not support thinking.
+func (client *Client) CycleThinkingLevel(ctx context.Context) (ThinkingLevel, error) {
+	response, err := client.send(ctx, cycleThinkingLevelCommand())
+	if err != nil {
+		return "", err
+	}
+	return decodeThinkingLevelCycle(response.Data)
+}
+
+// GetMessages returns the session conversation; decode content with AgentMessage.Blocks.
+func (client *Client) GetMessages(ctx context.Context) ([]AgentMessage, error) {
+	response, err := client.send(ctx, getMessagesCommand())
+	if err != nil {
+		return nil, err
+	}
+	return decodeMessages(response.Data)
+}
+
+func (client *Client) GetSessionStats(ctx context.Context) (SessionStats, error) {
+	response, err := client.send(ctx, getSessionStatsCommand())
+	if err != nil {
+		return SessionStats{}, err
+	}
+	return decodeSessionStats(response.Data)
+}
+
+// Bash runs a shell command in the session and adds its output to context.
+// Unlike other requests it gets no default timeout; cancelling ctx while waiting
+// sends a best-effort abort_bash.
+func (client *Client) Bash(ctx context.Context, shellCommand string) (BashResult, error) {
+	command, err := bashCommand(shellCommand)
+	if err != nil {
+		return BashResult{}, err
+	}
+	response, err := client.send(ctx, command)
+	if err != nil {
+		return BashResult{}, err
+	}
+	return decodeBashResult(response.Data)
+}
+
+func (client *Client) AbortBash(ctx context.Context) error {
+	_, err := client.send(ctx, abortBashCommand())
+	return err
+}
+
 func (client *SessionClient) ExportHTML(ctx context.Context, outputPath string) (string, error) {
 	response, err := client.send(ctx, exportHTMLCommand(outputPath))
 	if err != nil {
@@ -68,3 +162,46 @@ func (client *SessionClient) ExportHTML(ctx context.Context, outputPath string)
 	}
 	return decodeExportPath(response.Data)
 }
+
+// SwitchSession loads an existing session file. It reports true when an
+// extension cancelled the switch.
+func (client *SessionClient) SwitchSession(ctx context.Context, sessionPath string) (bool, error) {
+	command, err := switchSessionCommand(sessionPath)
+	if err != nil {
+		return false, err
+	}
+	response, err := client.send(ctx, command)
+	if err != nil {
+		return false, err
+	}
+	cancelled, err := decodeCancelled(rpc.CommandSwitchSession, response.Data)
+	if err == nil && !cancelled {
+		client.noteSessionChange()
+	}
+	return cancelled, err
+}
+
+// Fork branches the session from a prior user message (see GetForkMessages).
+func (client *SessionClient) Fork(ctx context.Context, entryID string) (ForkResult, error) {
+	command, err := forkCommand(entryID)
+	if err != nil {
+		return ForkResult{}, err
+	}
+	response, err := client.send(ctx, command)
+	if err != nil {
+		return ForkResult{}, err
+	}
+	result, err := decodeForkResult(response.Data)
+	if err == nil && !result.Cancelled {
+		client.noteSessionChange()
+	}
+	return result, err
+}
+
+func (client *SessionClient) GetForkMessages(ctx context.Context) ([]ForkMessage, error) {
+	response, err := client.send(ctx, getForkMessagesCommand())
+	if err != nil {
+		return nil, err
+	}
+	return decodeForkMessages(response.Data)
+}
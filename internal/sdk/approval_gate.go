@@ -0,0 +1,200 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// approvalGateUITitle marks editor requests the generated extension uses to ask Go for a decision.
+const approvalGateUITitle = "pi-golang:approve-tool-call"
+
+type DecisionAction string
+
+const (
+	DecisionAllow DecisionAction = "allow"
+	DecisionDeny  DecisionAction = "deny"
+)
+
+// ToolCallRequest is one upstream tool_call awaiting approval.
+type ToolCallRequest struct {
+	ToolCallID string          `json:"toolCallId"`
+	ToolName   string          `json:"toolName"`
+	Input      json.RawMessage `json:"input,omitempty"`
+}
+
+// Decision answers a ToolCallRequest. Reason is shown to the model on deny.
+type Decision struct {
+	Action DecisionAction `json:"action"`
+	Reason string         `json:"reason,omitempty"`
+}
+
+// ApproveToolCallFunc decides whether a tool call may execute. ctx ends after
+// UITimeout, on Abort, or when the client closes.
+// Errors, unknown actions, and an ended ctx deny the call (fail closed).
+type ApproveToolCallFunc func(ctx context.Context, request ToolCallRequest) (Decision, error)
+
+type managedApprovalGate struct {
+	bundleDir     string
+	extensionPath string
+}
+
+func createManagedApprovalGate() (*managedApprovalGate, error) {
+	bundleDir, err := os.MkdirTemp("", "pi-golang-approval-gate-")
+	if err != nil {
+		return nil, fmt.Errorf("create approval gate temp dir: %w", err)
+	}
+
+	extensionPath := filepath.Join(bundleDir, "approval-gate.ts")
+	if err := os.WriteFile(extensionPath, []byte(renderApprovalGateExtension()), 0o600); err != nil {
+		_ = os.RemoveAll(bundleDir)
+		return nil, fmt.Errorf("write approval gate extension: %w", err)
+	}
+
+	return &managedApprovalGate{bundleDir: bundleDir, extensionPath: extensionPath}, nil
+}
+
+func (gate *managedApprovalGate) arguments() []string {
+	if gate == nil {
+		return nil
+	}
+	return []string{"--extension", gate.extensionPath}
+}
+
+func (gate *managedApprovalGate) injectEnvironment(map[string]string) {}
+
+func (gate *managedApprovalGate) cleanup() error {
+	if gate == nil || gate.bundleDir == "" {
+		return nil
+	}
+	return os.RemoveAll(gate.bundleDir)
+}
+
+func isApprovalRequest(request ExtensionUIRequest) bool {
+	return request.Method == UIMethodEditor && request.Title == approvalGateUITitle
+}
+
+// startToolCallDecision registers one approval request and returns the work
+// that answers it, publishing tool_call_denied on deny. The decision is bounded
+// like a UI dialog (UITimeout or the upstream timeout) and cancelled by Abort.
+func (client *Client) startToolCallDecision(request ExtensionUIRequest) func() uiAnswer {
+	var call ToolCallRequest
+	if err := json.Unmarshal([]byte(request.Prefill), &call); err != nil {
+		return func() uiAnswer {
+			return client.answerToolCall(call, Decision{Action: DecisionDeny, Reason: fmt.Sprintf("decode tool call: %v", err)})
+		}
+	}
+
+	ctx, cancel := client.uiContext(request)
+	done := client.hostCalls.begin(request.ID, call.ToolCallID, cancel)
+	return func() uiAnswer {
+		defer done()
+		defer cancel()
+		return client.answerToolCall(call, client.approveToolCallDecision(ctx, call))
+	}
+}
+
+func (client *Client) answerToolCall(call ToolCallRequest, decision Decision) uiAnswer {
+	if decision.Action == DecisionDeny {
+		client.enqueueEvent(newToolCallDeniedEvent(call, decision.Reason))
+	}
+	encoded, _ := json.Marshal(decision)
+	return uiAnswer{value: string(encoded)}
+}
+
+// approveToolCallDecision stops waiting once ctx ends, even if the callback
+// ignores it, and denies the call.
+func (client *Client) approveToolCallDecision(ctx context.Context, call ToolCallRequest) Decision {
+	type outcome struct {
+		decision Decision
+		err      error
+	}
+	result := make(chan outcome, 1)
+	go func() {
+		decision, err := client.approveToolCall(ctx, call)
+		result <- outcome{decision: decision, err: err}
+	}()
+
+	var decided outcome
+	select {
+	case decided = <-result:
+	case <-ctx.Done():
+	}
+	if ctx.Err() != nil {
+		return Decision{Action: DecisionDeny, Reason: "approval interrupted: " + client.approvalInterruption(ctx)}
+	}
+	if decided.err != nil {
+		return Decision{Action: DecisionDeny, Reason: fmt.Sprintf("approval failed: %v", decided.err)}
+	}
+	switch decided.decision.Action {
+	case DecisionAllow:
+		return Decision{Action: DecisionAllow}
+	case DecisionDeny:
+		return Decision{Action: DecisionDeny, Reason: strings.TrimSpace(decided.decision.Reason)}
+	default:
+		return Decision{Action: DecisionDeny, Reason: fmt.Sprintf("invalid approval action %q", decided.decision.Action)}
+	}
+}
+
+func (client *Client) approvalInterruption(ctx context.Context) string {
+	switch {
+	case client.isClosed():
+		return "client closed"
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return "timed out"
+	default:
+		return "run aborted"
+	}
+}
+
+func newToolCallDeniedEvent(call ToolCallRequest, reason string) Event {
+	raw, _ := json.Marshal(map[string]any{
+		"type":       EventTypeToolCallDenied,
+		"toolCallId": call.ToolCallID,
+		"toolName":   call.ToolName,
+		"input":      call.Input,
+		"reason":     reason,
+	})
+	return Event{Type: EventTypeToolCallDenied, Raw: raw, ReceivedAt: time.Now()}
+}
+
+func renderApprovalGateExtension() string {
+	return fmt.Sprintf(`import type { ExtensionAPI } from "@mariozechner/pi-coding-agent";
+
+const APPROVAL_TITLE = %q;
+
+type Decision = {
+	action?: string;
+	reason?: string;
+};
+
+export default function (pi: ExtensionAPI) {
+	pi.on("tool_call", async (event, ctx) => {
+		const request = JSON.stringify({
+			toolCallId: event.toolCallId,
+			toolName: event.toolName,
+			input: event.input,
+		});
+
+		let decision: Decision = {};
+		try {
+			const raw = await ctx.ui.editor(APPROVAL_TITLE, request);
+			decision = raw === undefined ? {} : (JSON.parse(raw) as Decision);
+		} catch {
+			decision = {};
+		}
+
+		if (decision.action === "allow") return;
+		return {
+			block: true,
+			reason: decision.reason || "Tool call denied by host approval policy",
+		};
+	});
+}
+`, approvalGateUITitle)
+}
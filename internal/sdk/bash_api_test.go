@@ -0,0 +1,84 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/joshp123/pi-golang/internal/rpc"
+	"github.com/joshp123/pi-golang/internal/testsupport"
+)
+
+func TestBashCommandRequiresCommand(t *testing.T) {
+	if _, err := bashCommand("  "); err == nil {
+		t.Fatal("expected missing command error")
+	}
+	command, err := bashCommand("git status")
+	if err != nil {
+		t.Fatalf("bashCommand returned error: %v", err)
+	}
+	if command["type"] != rpc.CommandBash || command["command"] != "git status" {
+		t.Fatalf("unexpected bash command: %+v", command)
+	}
+}
+
+func TestDecodeBashResultRequiresData(t *testing.T) {
+	for _, data := range []string{"", "null"} {
+		if _, err := decodeBashResult(json.RawMessage(data)); !errors.Is(err, ErrProtocolViolation) {
+			t.Fatalf("expected protocol violation for %q, got %v", data, err)
+		}
+	}
+}
+
+func TestBashMirrorDecodesResult(t *testing.T) {
+	testsupport.SetupFakePI(t, "happy")
+
+	client, err := StartOneShot(testModelOneShotOptions())
+	if err != nil {
+		t.Fatalf("StartOneShot returned error: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	result, err := client.Bash(ctx, "git status")
+	if err != nil {
+		t.Fatalf("Bash returned error: %v", err)
+	}
+	if result.Output != "On branch main\n" || result.ExitCode == nil || *result.ExitCode != 0 || result.Truncated {
+		t.Fatalf("unexpected bash result: %+v", result)
+	}
+
+	truncated, err := client.Bash(ctx, "make test")
+	if err != nil {
+		t.Fatalf("Bash returned error: %v", err)
+	}
+	if !truncated.Truncated || truncated.FullOutputPath != "/tmp/pi-bash-1.log" || truncated.ExitCode == nil || *truncated.ExitCode != 2 {
+		t.Fatalf("unexpected truncated bash result: %+v", truncated)
+	}
+}
+
+func TestBashContextCancellationSendsAbortBash(t *testing.T) {
+	testsupport.SetupFakePI(t, "bash_abort")
+
+	client, err := StartOneShot(testModelOneShotOptions())
+	if err != nil {
+		t.Fatalf("StartOneShot returned error: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.Bash(ctx, "sleep 60"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+
+	state, err := client.GetState(context.Background())
+	if err != nil {
+		t.Fatalf("expected abort_bash to be observed, GetState error: %v", err)
+	}
+	if state.SessionID != "abort-bash-observed" {
+		t.Fatalf("unexpected session id: %q", state.SessionID)
+	}
+}
@@ -0,0 +1,125 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Budget mechanics:
+//  1. Count turns (turn_start) and tool calls (tool_execution_start) as they stream.
+//  2. Track token/cost usage: the in-flight assistant message's usage from
+//     message_update, folded into the run total at turn_end.
+//  3. When any limit trips, abort the run, collect events until agent_end (or
+//     AbortGrace), and return *BudgetExceededError with the partial result.
+//
+// MaxWallTime rides on the run context (deadline cause errBudgetWallTime), so
+// the usual ctx-cancel abort path applies.
+
+var errBudgetWallTime = errors.New("run wall time budget exceeded")
+
+type budgetTracker struct {
+	budget    Budget
+	startedAt time.Time
+	spent     BudgetUsage
+	// inflight is the usage of the assistant message still streaming.
+	inflight Usage
+}
+
+func newBudgetTracker(budget Budget, startedAt time.Time) *budgetTracker {
+	return &budgetTracker{budget: budget, startedAt: startedAt}
+}
+
+// context bounds ctx by MaxWallTime, if set.
+func (tracker *budgetTracker) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if tracker.budget.MaxWallTime <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithDeadlineCause(ctx, tracker.startedAt.Add(tracker.budget.MaxWallTime), errBudgetWallTime)
+}
+
+// wallTimeExceeded reports whether runCtx ended because of MaxWallTime rather
+// than the caller's ctx.
+func (tracker *budgetTracker) wallTimeExceeded(ctx context.Context, runCtx context.Context) bool {
+	return ctx.Err() == nil && errors.Is(context.Cause(runCtx), errBudgetWallTime)
+}
+
+func (tracker *budgetTracker) tracksUsage() bool {
+	return tracker.budget.MaxInputTokens > 0 || tracker.budget.MaxOutputTokens > 0 || tracker.budget.MaxCostUSD > 0
+}
+
+// observe records one event and returns the limit it tripped, if any.
+func (tracker *budgetTracker) observe(event Event) (BudgetLimit, bool) {
+	switch event.Type {
+	case EventTypeTurnStart:
+		tracker.spent.Turns++
+	case EventTypeToolExecutionStart:
+		tracker.spent.ToolCalls++
+	case EventTypeMessageUpdate:
+		if !tracker.tracksUsage() {
+			return "", false
+		}
+		parsed, err := DecodeMessageUpdate(event.Raw)
+		if err == nil && parsed.Message.Usage != nil {
+			tracker.inflight = *parsed.Message.Usage
+		}
+	case EventTypeTurnEnd:
+		if !tracker.tracksUsage() {
+			return "", false
+		}
+		parsed, err := DecodeTurnEnd(event.Raw)
+		if err == nil && parsed.Message.Usage != nil {
+			tracker.inflight = *parsed.Message.Usage
+		}
+		tracker.spent.InputTokens, tracker.spent.OutputTokens, tracker.spent.CostUSD = tracker.totals()
+		tracker.inflight = Usage{}
+	default:
+		return "", false
+	}
+	return tracker.exceeded()
+}
+
+// totals is committed usage plus the in-flight message.
+func (tracker *budgetTracker) totals() (int, int, float64) {
+	input := tracker.spent.InputTokens + tracker.inflight.Input + tracker.inflight.CacheRead + tracker.inflight.CacheWrite
+	output := tracker.spent.OutputTokens + tracker.inflight.Output
+	cost := tracker.spent.CostUSD
+	if tracker.inflight.Cost != nil {
+		cost += tracker.inflight.Cost.Total
+	}
+	return input, output, cost
+}
+
+func (tracker *budgetTracker) exceeded() (BudgetLimit, bool) {
+	budget := tracker.budget
+	input, output, cost := tracker.totals()
+	switch {
+	case budget.MaxInputTokens > 0 && input > budget.MaxInputTokens:
+		return BudgetLimitInputTokens, true
+	case budget.MaxOutputTokens > 0 && output > budget.MaxOutputTokens:
+		return BudgetLimitOutputTokens, true
+	case budget.MaxCostUSD > 0 && cost > budget.MaxCostUSD:
+		return BudgetLimitCost, true
+	case budget.MaxTurns > 0 && tracker.spent.Turns > budget.MaxTurns:
+		return BudgetLimitTurns, true
+	case budget.MaxToolCalls > 0 && tracker.spent.ToolCalls > budget.MaxToolCalls:
+		return BudgetLimitToolCalls, true
+	}
+	return "", false
+}
+
+// usage snapshots spend so far, including the in-flight message.
+func (tracker *budgetTracker) usage(now time.Time) BudgetUsage {
+	spent := tracker.spent
+	spent.InputTokens, spent.OutputTokens, spent.CostUSD = tracker.totals()
+	spent.WallTime = now.Sub(tracker.startedAt)
+	return spent
+}
+
+func (tracker *budgetTracker) exceededError(limit BudgetLimit, now time.Time) *BudgetExceededError {
+	return &BudgetExceededError{
+		Limit:  limit,
+		Budget: tracker.budget,
+		Spent:  tracker.usage(now),
+	}
+}
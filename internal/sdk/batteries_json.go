@@ -0,0 +1,150 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// RunJSON mechanics:
+//  1. Derive a JSON schema from T and append format instructions to the prompt.
+//  2. Extract one JSON value from the assistant text, validate it, decode into T.
+//  3. On extraction/validation failure, send the error back as a follow-up (a
+//     repair attempt) up to MaxRepairs times. Repairs are prompt commands with
+//     StreamingBehaviorFollowUp: pi queues them behind any turn still running
+//     and starts a turn when the agent is idle, where a bare follow_up would
+//     only sit in the queue.
+
+const defaultJSONMaxRepairs = 2
+
+var fencedJSONPattern = regexp.MustCompile("(?s)```(?:json|JSON)?[ \t]*\r?\n(.*?)```")
+
+// DetailedRunner is satisfied by every client type. Pool.RunDetailed borrows a
+// different client per call, which would send repairs to a fresh conversation;
+// run RunJSON inside Pool.With instead.
+type DetailedRunner interface {
+	RunDetailed(ctx context.Context, request PromptRequest) (RunDetailedResult, error)
+}
+
+type JSONOptions struct {
+	// MaxRepairs bounds repair prompts after the first attempt.
+	// 0 uses the default (2); negative disables repairs.
+	MaxRepairs int
+}
+
+type JSONResult[T any] struct {
+	Value T
+	// Attempts holds every run in order: the initial prompt, then each repair.
+	Attempts []RunDetailedResult
+}
+
+func RunJSON[T any](ctx context.Context, runner DetailedRunner, request PromptRequest, options JSONOptions) (JSONResult[T], error) {
+	if ctx == nil {
+		return JSONResult[T]{}, ErrNilContext
+	}
+	if runner == nil {
+		return JSONResult[T]{}, errors.New("runner is required")
+	}
+	maxRepairs := options.MaxRepairs
+	if maxRepairs == 0 {
+		maxRepairs = defaultJSONMaxRepairs
+	}
+	maxRepairs = max(maxRepairs, 0)
+
+	schema := schemaFor(reflect.TypeFor[T]())
+	instructions, err := jsonFormatInstructions(schema)
+	if err != nil {
+		return JSONResult[T]{}, err
+	}
+	request.Message = strings.TrimRight(request.Message, "\n") + "\n\n" + instructions
+
+	var result JSONResult[T]
+	for attempt := 0; ; attempt++ {
+		detailed, err := runner.RunDetailed(ctx, request)
+		if err != nil {
+			return result, err
+		}
+		result.Attempts = append(result.Attempts, detailed)
+
+		outcome := detailed.Outcome
+		if outcome.Status != TerminalStatusCompleted {
+			return result, fmt.Errorf("structured output attempt %d %s: %s", attempt+1, outcome.Status, outcome.ErrorMessage)
+		}
+
+		value, validationErr := decodeStructuredOutput[T](schema, outcome.Text)
+		if validationErr == nil {
+			result.Value = value
+			return result, nil
+		}
+		if attempt >= maxRepairs {
+			return result, fmt.Errorf("%w after %d attempts: %v", ErrInvalidStructuredOutput, attempt+1, validationErr)
+		}
+		request = PromptRequest{Message: jsonRepairPrompt(validationErr), StreamingBehavior: StreamingBehaviorFollowUp}
+	}
+}
+
+func jsonFormatInstructions(schema *jsonSchema) (string, error) {
+	encoded, err := json.MarshalIndent(schema.document(), "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("encode json schema: %w", err)
+	}
+	return "Respond with a single JSON value matching this JSON Schema. " +
+		"Output only the JSON, with no surrounding prose.\n\n```json\n" + string(encoded) + "\n```", nil
+}
+
+func jsonRepairPrompt(validationErr error) string {
+	return "Your previous response was not valid: " + validationErr.Error() +
+		"\n\nRespond again with only the corrected JSON value matching the schema."
+}
+
+func decodeStructuredOutput[T any](schema *jsonSchema, text string) (T, error) {
+	var value T
+	raw, err := extractJSON(text)
+	if err != nil {
+		return value, err
+	}
+	var generic any
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return value, fmt.Errorf("invalid JSON: %v", err)
+	}
+	if err := schema.validate(generic); err != nil {
+		return value, err
+	}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return value, fmt.Errorf("decode into %s: %v", reflect.TypeFor[T](), err)
+	}
+	return value, nil
+}
+
+// extractJSON finds one JSON value in assistant text: the whole text, a fenced
+// code block, or the first object/array that decodes from an opening brace.
+func extractJSON(text string) (json.RawMessage, error) {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return nil, errors.New("response is empty; expected a JSON value")
+	}
+	if json.Valid([]byte(trimmed)) {
+		return json.RawMessage(trimmed), nil
+	}
+	for _, match := range fencedJSONPattern.FindAllStringSubmatch(trimmed, -1) {
+		candidate := strings.TrimSpace(match[1])
+		if json.Valid([]byte(candidate)) {
+			return json.RawMessage(candidate), nil
+		}
+	}
+	for index, char := range trimmed {
+		if char != '{' && char != '[' {
+			continue
+		}
+		var raw json.RawMessage
+		if err := json.NewDecoder(strings.NewReader(trimmed[index:])).Decode(&raw); err == nil {
+			return bytes.TrimSpace(raw), nil
+		}
+	}
+	return nil, errors.New("no JSON value found in response")
+}
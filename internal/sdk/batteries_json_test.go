@@ -0,0 +1,272 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type schemaFixtureBase struct {
+	ID string `json:"id"`
+}
+
+type schemaFixture struct {
+	schemaFixtureBase
+	Name      string          `json:"name"`
+	Count     int             `json:"count"`
+	Score     float64         `json:"score,omitempty"`
+	Tags      []string        `json:"tags"`
+	Labels    map[string]bool `json:"labels,omitempty"`
+	Parent    *schemaFixture  `json:"parent,omitempty"`
+	When      time.Time       `json:"when"`
+	Extra     json.RawMessage `json:"extra,omitempty"`
+	Ignored   string          `json:"-"`
+	unexposed string
+	Untagged  bool
+	Nested    map[string][]int8 `json:"nested,omitempty"`
+}
+
+func TestSchemaForDerivesObjectSchema(t *testing.T) {
+	document := schemaFor(reflect.TypeFor[schemaFixture]()).document()
+
+	if document["type"] != "object" || document["additionalProperties"] != false {
+		t.Fatalf("unexpected root schema: %+v", document)
+	}
+	required := document["required"].([]string)
+	if strings.Join(required, ",") != "Untagged,count,id,name,tags,when" {
+		t.Fatalf("unexpected required fields: %v", required)
+	}
+	properties := document["properties"].(map[string]any)
+	for _, hidden := range []string{"Ignored", "-", "unexposed"} {
+		if _, ok := properties[hidden]; ok {
+			t.Fatalf("unexpected property %q", hidden)
+		}
+	}
+	expectType := func(name string, want any) {
+		t.Helper()
+		got := properties[name].(map[string]any)["type"]
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("property %s type = %v, want %v", name, got, want)
+		}
+	}
+	expectType("id", "string")
+	expectType("count", "integer")
+	expectType("score", "number")
+	expectType("tags", []string{"array", "null"})
+	expectType("labels", []string{"object", "null"})
+	expectType("parent", nil)
+	expectType("when", "string")
+	expectType("extra", nil)
+	expectType("Untagged", "boolean")
+}
+
+func TestJSONSchemaValidateReportsPath(t *testing.T) {
+	type item struct {
+		Name  string `json:"name"`
+		Count *int   `json:"count"`
+	}
+	type payload struct {
+		Items []item `json:"items"`
+	}
+	schema := schemaFor(reflect.TypeFor[payload]())
+
+	cases := map[string]string{
+		`{"items":[{"name":"a","count":1}]}`:          "",
+		`{"items":[{"name":"a","count":null}]}`:       "",
+		`{"items":[{"name":"a"}]}`:                    `$.items[0]: missing required property "count"`,
+		`{"items":[{"name":1,"count":1}]}`:            "$.items[0].name: expected string, got number",
+		`{"items":[{"name":"a","count":1.5}]}`:        "$.items[0].count: expected integer, got number",
+		`{"items":[{"name":"a","count":1,"x":true}]}`: `$.items[0]: unexpected property "x"`,
+		`{"items":null}`:                              "",
+		`{"items":[null]}`:                            "$.items[0]: expected object, got null",
+		`[]`:                                          "$: expected object, got array",
+	}
+	for input, want := range cases {
+		var value any
+		if err := json.Unmarshal([]byte(input), &value); err != nil {
+			t.Fatalf("bad fixture %s: %v", input, err)
+		}
+		err := schema.validate(value)
+		switch {
+		case want == "" && err != nil:
+			t.Fatalf("validate(%s) returned error: %v", input, err)
+		case want != "" && (err == nil || err.Error() != want):
+			t.Fatalf("validate(%s) = %v, want %q", input, err, want)
+		}
+	}
+}
+
+func TestSchemaForMatchesEncodingJSONForBytesAndUnsigned(t *testing.T) {
+	type payload struct {
+		Blob   []byte   `json:"blob"`
+		Digest [4]byte  `json:"digest"`
+		Size   uint     `json:"size"`
+		Delta  int      `json:"delta"`
+		Ports  []uint16 `json:"ports"`
+	}
+	schema := schemaFor(reflect.TypeFor[payload]())
+	properties := schema.document()["properties"].(map[string]any)
+	if blob := properties["blob"].(map[string]any); !reflect.DeepEqual(blob["type"], []string{"string", "null"}) || blob["format"] != "byte" {
+		t.Fatalf("unexpected []byte schema: %+v", blob)
+	}
+	if digest := properties["digest"].(map[string]any); digest["type"] != "array" {
+		t.Fatalf("unexpected [N]byte schema: %+v", digest)
+	}
+	if size := properties["size"].(map[string]any); size["minimum"] != 0 {
+		t.Fatalf("expected minimum 0 on unsigned schema: %+v", size)
+	}
+	if _, ok := properties["delta"].(map[string]any)["minimum"]; ok {
+		t.Fatal("expected no minimum on signed schema")
+	}
+
+	encoded, err := json.Marshal(payload{Blob: []byte("hi"), Digest: [4]byte{1, 2, 3, 4}, Size: 7, Delta: -1, Ports: []uint16{80}})
+	if err != nil {
+		t.Fatalf("marshal fixture: %v", err)
+	}
+	if _, err := decodeStructuredOutput[payload](schema, string(encoded)); err != nil {
+		t.Fatalf("encoding/json output failed validation: %v", err)
+	}
+	negative := `{"blob":"","digest":[0,0,0,0],"size":1,"delta":0,"ports":[-1]}`
+	if _, err := decodeStructuredOutput[payload](schema, negative); err == nil || err.Error() != "$.ports[0]: expected non-negative integer, got -1" {
+		t.Fatalf("expected negative unsigned to fail validation, got %v", err)
+	}
+}
+
+func TestSchemaForMatchesEncodingJSONForStringOptionNilCollectionsAndLargeUnsigned(t *testing.T) {
+	type payload struct {
+		Quoted   int64             `json:"quoted,string"`
+		Optional *bool             `json:"optional,string"`
+		Names    []string          `json:"names"`
+		Blob     []byte            `json:"blob"`
+		Labels   map[string]string `json:"labels"`
+		Digest   [2]byte           `json:"digest"`
+		Big      uint64            `json:"big"`
+	}
+	schema := schemaFor(reflect.TypeFor[payload]())
+
+	encoded, err := json.Marshal(payload{Quoted: 7, Big: math.MaxUint64})
+	if err != nil {
+		t.Fatalf("marshal fixture: %v", err)
+	}
+	if _, err := decodeStructuredOutput[payload](schema, string(encoded)); err != nil {
+		t.Fatalf("encoding/json output %s failed validation: %v", encoded, err)
+	}
+
+	valid := `{"quoted":"1","optional":"true","names":[],"blob":"","labels":{},"digest":[0,0],"big":0}`
+	cases := []struct {
+		name string
+		from string
+		to   string
+		want string
+	}{
+		{name: "string option", from: `"quoted":"1"`, to: `"quoted":"12"`},
+		{name: "string option rejects bare number", from: `"quoted":"1"`, to: `"quoted":1`, want: "$.quoted: expected string, got number"},
+		{name: "string option on pointer is nullable", from: `"optional":"true"`, to: `"optional":null`},
+		{name: "nil slice", from: `"names":[]`, to: `"names":null`},
+		{name: "nil bytes", from: `"blob":""`, to: `"blob":null`},
+		{name: "nil map", from: `"labels":{}`, to: `"labels":null`},
+		{name: "array stays non-nullable", from: `"digest":[0,0]`, to: `"digest":null`, want: "$.digest: expected array, got null"},
+		{name: "uint64 above 2^63", from: `"big":0`, to: `"big":18446744073709551615`},
+		{name: "unsigned negative", from: `"big":0`, to: `"big":-1`, want: "$.big: expected non-negative integer, got -1"},
+	}
+	for _, tc := range cases {
+		input := strings.Replace(valid, tc.from, tc.to, 1)
+		var value any
+		if err := json.Unmarshal([]byte(input), &value); err != nil {
+			t.Fatalf("%s: bad fixture %s: %v", tc.name, input, err)
+		}
+		err := schema.validate(value)
+		switch {
+		case tc.want == "" && err != nil:
+			t.Fatalf("%s: validate(%s) returned error: %v", tc.name, input, err)
+		case tc.want != "" && (err == nil || err.Error() != tc.want):
+			t.Fatalf("%s: validate(%s) = %v, want %q", tc.name, input, err, tc.want)
+		}
+	}
+}
+
+func TestExtractJSONFindsValueInProse(t *testing.T) {
+	cases := map[string]string{
+		` {"a":1} `:                                 `{"a":1}`,
+		"Here:\n```json\n{\"a\":2}\n```\nDone.":     `{"a":2}`,
+		"Result: {\"a\":3} hope that helps {oops}":  `{"a":3}`,
+		"Use {braces} like [this] or: [1, 2, 3] ok": `[1, 2, 3]`,
+	}
+	for input, want := range cases {
+		raw, err := extractJSON(input)
+		if err != nil {
+			t.Fatalf("extractJSON(%q) returned error: %v", input, err)
+		}
+		if string(raw) != want {
+			t.Fatalf("extractJSON(%q) = %s, want %s", input, raw, want)
+		}
+	}
+	if _, err := extractJSON("no json here"); err == nil {
+		t.Fatal("expected error when no JSON value is present")
+	}
+}
+
+type scriptedRunner struct {
+	replies  []string
+	requests []PromptRequest
+}
+
+func (runner *scriptedRunner) RunDetailed(_ context.Context, request PromptRequest) (RunDetailedResult, error) {
+	runner.requests = append(runner.requests, request)
+	text := runner.replies[min(len(runner.requests), len(runner.replies))-1]
+	return RunDetailedResult{Outcome: TerminalOutcome{Status: TerminalStatusCompleted, Text: text}}, nil
+}
+
+func TestRunJSONStopsAfterMaxRepairs(t *testing.T) {
+	type answer struct {
+		OK bool `json:"ok"`
+	}
+	runner := &scriptedRunner{replies: []string{"not json"}}
+
+	result, err := RunJSON[answer](context.Background(), runner, PromptRequest{Message: "check"}, JSONOptions{MaxRepairs: 1})
+	if !errors.Is(err, ErrInvalidStructuredOutput) {
+		t.Fatalf("expected ErrInvalidStructuredOutput, got %v", err)
+	}
+	if len(result.Attempts) != 2 || len(runner.requests) != 2 {
+		t.Fatalf("expected 2 attempts, got %d results / %d requests", len(result.Attempts), len(runner.requests))
+	}
+	if !strings.Contains(runner.requests[0].Message, `"additionalProperties": false`) {
+		t.Fatalf("expected schema in first prompt: %s", runner.requests[0].Message)
+	}
+	if !strings.Contains(runner.requests[1].Message, "no JSON value found") {
+		t.Fatalf("expected validation error in repair prompt: %s", runner.requests[1].Message)
+	}
+	if runner.requests[0].StreamingBehavior != "" || runner.requests[1].StreamingBehavior != StreamingBehaviorFollowUp {
+		t.Fatalf("expected only the repair to be sent as a follow-up: %+v", runner.requests)
+	}
+
+	runner = &scriptedRunner{replies: []string{"not json"}}
+	if _, err := RunJSON[answer](context.Background(), runner, PromptRequest{Message: "check"}, JSONOptions{MaxRepairs: -1}); !errors.Is(err, ErrInvalidStructuredOutput) {
+		t.Fatalf("expected ErrInvalidStructuredOutput, got %v", err)
+	}
+	if len(runner.requests) != 1 {
+		t.Fatalf("expected no repairs when disabled, got %d requests", len(runner.requests))
+	}
+}
+
+func TestRunJSONFailsFastOnIncompleteRun(t *testing.T) {
+	runner := &abortedRunner{}
+	result, err := RunJSON[map[string]any](context.Background(), runner, PromptRequest{Message: "check"}, JSONOptions{})
+	if err == nil || errors.Is(err, ErrInvalidStructuredOutput) {
+		t.Fatalf("expected non-repairable error, got %v", err)
+	}
+	if len(result.Attempts) != 1 {
+		t.Fatalf("expected one attempt, got %d", len(result.Attempts))
+	}
+}
+
+type abortedRunner struct{}
+
+func (abortedRunner) RunDetailed(context.Context, PromptRequest) (RunDetailedResult, error) {
+	return RunDetailedResult{Outcome: TerminalOutcome{Status: TerminalStatusAborted}}, nil
+}
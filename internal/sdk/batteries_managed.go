@@ -13,12 +13,33 @@ import (
 // - ClassifyRunError classifies runtime/process failures separately from terminal outcome classes.
 
 func ClassifyManaged(result RunDetailedResult) ManagedSummary {
+	compactions := managedCompactions(result)
+	retries := managedRetries(result)
+
 	facts := RecoveryFacts{
-		CompactionObserved: result.AutoCompactionStart != nil || result.AutoCompactionEnd != nil,
-		OverflowDetected:   isOverflowCompaction(result.AutoCompactionStart),
+		CompactionObserved: len(compactions) > 0,
+		Compactions:        len(compactions),
+		RetryAttempts:      len(retries),
+	}
+	recoveries := 0
+	for _, episode := range compactions {
+		if !isOverflowCompaction(episode.Start) {
+			continue
+		}
+		facts.OverflowDetected = true
+		facts.OverflowCompactions++
+		if compactionSucceeded(episode.End) {
+			recoveries++
+		}
+	}
+	for _, attempt := range retries {
+		if attempt.End != nil && attempt.End.Success {
+			facts.RetriesRecovered++
+			recoveries++
+		}
 	}
 
-	recovered := facts.OverflowDetected && compactionSucceeded(result.AutoCompactionEnd) && result.Outcome.Status == TerminalStatusCompleted
+	recovered := recoveries > 0 && result.Outcome.Status == TerminalStatusCompleted
 	facts.Recovered = recovered
 
 	return ManagedSummary{
@@ -27,6 +48,30 @@ func ClassifyManaged(result RunDetailedResult) ManagedSummary {
 	}
 }
 
+// managedCompactions returns the episode timeline, falling back to the
+// last-seen pointers for results built without it.
+func managedCompactions(result RunDetailedResult) []CompactionEpisode {
+	if len(result.Compactions) > 0 || (result.AutoCompactionStart == nil && result.AutoCompactionEnd == nil) {
+		return result.Compactions
+	}
+	episode := CompactionEpisode{End: result.AutoCompactionEnd}
+	if result.AutoCompactionStart != nil {
+		episode.Start = *result.AutoCompactionStart
+	}
+	return []CompactionEpisode{episode}
+}
+
+func managedRetries(result RunDetailedResult) []RetryAttempt {
+	if len(result.Retries) > 0 || (result.AutoRetryStart == nil && result.AutoRetryEnd == nil) {
+		return result.Retries
+	}
+	attempt := RetryAttempt{End: result.AutoRetryEnd}
+	if result.AutoRetryStart != nil {
+		attempt.Start = *result.AutoRetryStart
+	}
+	return []RetryAttempt{attempt}
+}
+
 func ClassifyRunError(err error) (BrokenCause, bool) {
 	if err == nil {
 		return "", false
@@ -46,10 +91,7 @@ func ClassifyRunError(err error) (BrokenCause, bool) {
 	return "", false
 }
 
-func isOverflowCompaction(event *AutoCompactionStartEvent) bool {
-	if event == nil {
-		return false
-	}
+func isOverflowCompaction(event AutoCompactionStartEvent) bool {
 	return strings.EqualFold(strings.TrimSpace(event.Reason), "overflow")
 }
 
@@ -32,9 +32,11 @@ func TestClassifyManaged(t *testing.T) {
 			},
 			wantClass: CompletionClassOKAfterRecovery,
 			wantFacts: RecoveryFacts{
-				CompactionObserved: true,
-				OverflowDetected:   true,
-				Recovered:          true,
+				CompactionObserved:  true,
+				OverflowDetected:    true,
+				Recovered:           true,
+				Compactions:         1,
+				OverflowCompactions: 1,
 			},
 		},
 		{
@@ -45,7 +47,7 @@ func TestClassifyManaged(t *testing.T) {
 				AutoCompactionEnd:   &AutoCompactionEndEvent{Result: compactionResult},
 			},
 			wantClass: CompletionClassOK,
-			wantFacts: RecoveryFacts{CompactionObserved: true},
+			wantFacts: RecoveryFacts{CompactionObserved: true, Compactions: 1},
 		},
 		{
 			name: "completed_overflow_compaction_failed",
@@ -56,9 +58,11 @@ func TestClassifyManaged(t *testing.T) {
 			},
 			wantClass: CompletionClassOK,
 			wantFacts: RecoveryFacts{
-				CompactionObserved: true,
-				OverflowDetected:   true,
-				Recovered:          false,
+				CompactionObserved:  true,
+				OverflowDetected:    true,
+				Recovered:           false,
+				Compactions:         1,
+				OverflowCompactions: 1,
 			},
 		},
 		{
@@ -70,9 +74,55 @@ func TestClassifyManaged(t *testing.T) {
 			},
 			wantClass: CompletionClassAborted,
 			wantFacts: RecoveryFacts{
-				CompactionObserved: true,
-				OverflowDetected:   true,
-				Recovered:          false,
+				CompactionObserved:  true,
+				OverflowDetected:    true,
+				Recovered:           false,
+				Compactions:         1,
+				OverflowCompactions: 1,
+			},
+		},
+		{
+			name: "completed_after_retry_sequence",
+			result: RunDetailedResult{
+				Outcome: TerminalOutcome{Status: TerminalStatusCompleted},
+				Retries: []RetryAttempt{
+					{Start: AutoRetryStartEvent{Attempt: 1}},
+					{Start: AutoRetryStartEvent{Attempt: 2}, End: &AutoRetryEndEvent{Success: true, Attempt: 2}},
+				},
+			},
+			wantClass: CompletionClassOKAfterRecovery,
+			wantFacts: RecoveryFacts{Recovered: true, RetryAttempts: 2, RetriesRecovered: 1},
+		},
+		{
+			name: "completed_retries_exhausted",
+			result: RunDetailedResult{
+				Outcome:        TerminalOutcome{Status: TerminalStatusCompleted},
+				AutoRetryStart: &AutoRetryStartEvent{Attempt: 3},
+				AutoRetryEnd:   &AutoRetryEndEvent{Success: false, Attempt: 3, FinalError: "overloaded"},
+			},
+			wantClass: CompletionClassOK,
+			wantFacts: RecoveryFacts{RetryAttempts: 1},
+		},
+		{
+			name: "later_overflow_compaction_recovers",
+			result: RunDetailedResult{
+				Outcome: TerminalOutcome{Status: TerminalStatusCompleted},
+				// Last-seen pointers describe only the final (threshold) episode.
+				AutoCompactionStart: &AutoCompactionStartEvent{Reason: "threshold"},
+				AutoCompactionEnd:   &AutoCompactionEndEvent{Result: compactionResult},
+				Compactions: []CompactionEpisode{
+					{Start: AutoCompactionStartEvent{Reason: "overflow"}, End: &AutoCompactionEndEvent{ErrorMessage: "boom"}},
+					{Start: AutoCompactionStartEvent{Reason: "overflow"}, End: &AutoCompactionEndEvent{Result: compactionResult}},
+					{Start: AutoCompactionStartEvent{Reason: "threshold"}, End: &AutoCompactionEndEvent{Result: compactionResult}},
+				},
+			},
+			wantClass: CompletionClassOKAfterRecovery,
+			wantFacts: RecoveryFacts{
+				CompactionObserved:  true,
+				OverflowDetected:    true,
+				Recovered:           true,
+				Compactions:         3,
+				OverflowCompactions: 2,
 			},
 		},
 		{
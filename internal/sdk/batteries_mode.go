@@ -0,0 +1,38 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+)
+
+// Batteries layer: live mode switching on top of set_model + set_thinking_level.
+//
+// SwitchMode mechanics:
+//  1. Resolve the mode to provider/model/thinking (same table as startup).
+//  2. Presence-validate auth for the target provider before touching the process.
+//  3. Send set_model, then set_thinking_level.
+
+func (client *Client) SwitchMode(ctx context.Context, mode Mode) (ModelInfo, error) {
+	if ctx == nil {
+		return ModelInfo{}, ErrNilContext
+	}
+	if mode == ModeDragons {
+		return ModelInfo{}, fmt.Errorf("mode %q requires explicit provider/model; use SetModel", ModeDragons)
+	}
+	config, err := resolveModelConfig(mode, DragonsOptions{})
+	if err != nil {
+		return ModelInfo{}, err
+	}
+	if err := validateProviderAuth(config.provider, client.auth); err != nil {
+		return ModelInfo{}, err
+	}
+
+	model, err := client.SetModel(ctx, config.provider, config.model)
+	if err != nil {
+		return ModelInfo{}, err
+	}
+	if err := client.SetThinkingLevel(ctx, ThinkingLevel(config.thinking)); err != nil {
+		return ModelInfo{}, err
+	}
+	return model, nil
+}
@@ -0,0 +1,283 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Pool mechanics:
+//  1. NewPool starts Size one-shot clients up front (startup errors fail fast).
+//  2. Each Run/RunDetailed borrows one idle client for a single prompt; With
+//     lends one for a whole callback (e.g. RunJSON with its repairs).
+//  3. Afterwards the client is discarded and a fresh one is started in the
+//     background, or with Reuse it is reset via new_session and returned.
+//     Clients that died or failed a run are always replaced.
+
+var (
+	defaultPoolSize       = 2
+	defaultPoolRetryDelay = time.Second
+	defaultPoolResetTime  = 5 * time.Second
+)
+
+type PoolOptions struct {
+	// Size is the number of warm clients kept (default 2).
+	Size int
+	// Reuse resets a client with new_session after a successful run instead of
+	// replacing its process. Faster; session-scoped process state (e.g. extension
+	// globals) carries over between runs.
+	Reuse bool
+}
+
+type PoolStats struct {
+	Size     int
+	Idle     int
+	InUse    int
+	Starting int
+	// Runs counts completed borrows; Started counts processes started (including
+	// the initial Size); Replaced counts clients swapped out after process death.
+	Runs          int
+	Started       int
+	Replaced      int
+	StartFailures int
+}
+
+// Pool hands out pre-started OneShotClients, one per run. Safe for concurrent use.
+type Pool struct {
+	options OneShotOptions
+	reuse   bool
+	idle    chan *OneShotClient
+	closed  chan struct{}
+	starts  sync.WaitGroup
+	logger  *slog.Logger
+
+	mu       sync.Mutex
+	isClosed bool
+	stats    PoolStats
+}
+
+func NewPool(options OneShotOptions, poolOptions PoolOptions) (*Pool, error) {
+	if poolOptions.Size < 0 {
+		return nil, fmt.Errorf("pool size must be >= 0")
+	}
+	size := poolOptions.Size
+	if size == 0 {
+		size = defaultPoolSize
+	}
+
+	pool := &Pool{
+		options: options,
+		reuse:   poolOptions.Reuse,
+		idle:    make(chan *OneShotClient, size),
+		closed:  make(chan struct{}),
+		logger:  resolveLogger(options.Logger),
+		stats:   PoolStats{Size: size},
+	}
+	for range size {
+		client, err := StartOneShot(options)
+		if err != nil {
+			_ = pool.Close()
+			return nil, err
+		}
+		pool.stats.Started++
+		pool.idle <- client
+	}
+	return pool, nil
+}
+
+func (pool *Pool) Run(ctx context.Context, request PromptRequest) (RunResult, error) {
+	detailed, err := pool.RunDetailed(ctx, request)
+	if err != nil {
+		return RunResult{}, err
+	}
+	return RunResult{Text: detailed.Outcome.Text, Usage: detailed.Outcome.Usage}, nil
+}
+
+// RunDetailed waits for an idle client (honoring ctx) and runs one prompt on it.
+func (pool *Pool) RunDetailed(ctx context.Context, request PromptRequest) (RunDetailedResult, error) {
+	return pool.RunDetailedWithOptions(ctx, request, RunOptions{})
+}
+
+func (pool *Pool) RunDetailedWithOptions(ctx context.Context, request PromptRequest, options RunOptions) (RunDetailedResult, error) {
+	if ctx == nil {
+		return RunDetailedResult{}, ErrNilContext
+	}
+	client, err := pool.acquire(ctx)
+	if err != nil {
+		return RunDetailedResult{}, err
+	}
+	result, runErr := client.RunDetailedWithOptions(ctx, request, options)
+	pool.release(client, runErr)
+	return result, runErr
+}
+
+// With borrows one idle client (honoring ctx) for the duration of fn, so
+// multi-prompt work such as RunJSON repairs stays in one conversation. The
+// client is released like a run's: fn's error decides reuse or replacement.
+// fn must not keep the client after returning.
+func (pool *Pool) With(ctx context.Context, fn func(*OneShotClient) error) (err error) {
+	if ctx == nil {
+		return ErrNilContext
+	}
+	if fn == nil {
+		return errors.New("fn is required")
+	}
+	client, err := pool.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { pool.release(client, err) }()
+	return fn(client)
+}
+
+func (pool *Pool) Stats() PoolStats {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	stats := pool.stats
+	stats.Idle = len(pool.idle)
+	return stats
+}
+
+// Close stops idle clients, waits for background starts, and makes further runs
+// fail with ErrClientClosed. Borrowed clients are closed when their run returns.
+func (pool *Pool) Close() error {
+	pool.mu.Lock()
+	if pool.isClosed {
+		pool.mu.Unlock()
+		return nil
+	}
+	pool.isClosed = true
+	close(pool.closed)
+	pool.mu.Unlock()
+
+	pool.starts.Wait()
+	var errs []error
+	for {
+		select {
+		case client := <-pool.idle:
+			errs = append(errs, client.Close())
+		default:
+			return errors.Join(errs...)
+		}
+	}
+}
+
+func (pool *Pool) acquire(ctx context.Context) (*OneShotClient, error) {
+	for {
+		select {
+		case <-pool.closed:
+			return nil, ErrClientClosed
+		default:
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-pool.closed:
+			return nil, ErrClientClosed
+		case client := <-pool.idle:
+			if client.terminalError() != nil {
+				pool.replace(client, true)
+				continue
+			}
+			// Close may have started after the first check; a client received
+			// here would otherwise race its drain.
+			pool.mu.Lock()
+			if pool.isClosed {
+				pool.mu.Unlock()
+				_ = client.Close()
+				return nil, ErrClientClosed
+			}
+			pool.stats.InUse++
+			pool.mu.Unlock()
+			return client, nil
+		}
+	}
+}
+
+func (pool *Pool) release(client *OneShotClient, runErr error) {
+	pool.mu.Lock()
+	pool.stats.InUse--
+	pool.stats.Runs++
+	pool.mu.Unlock()
+
+	died := errors.Is(runErr, ErrProcessDied) || client.currentProcessError() != nil
+	if runErr == nil && pool.reuse && pool.reset(client) {
+		pool.put(client)
+		return
+	}
+	pool.replace(client, died)
+}
+
+func (pool *Pool) reset(client *OneShotClient) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultPoolResetTime)
+	defer cancel()
+	cancelled, err := client.NewSession(ctx, "")
+	if err != nil || cancelled {
+		client.logger.Warn("pool reset failed", "cancelled", cancelled, "error", err)
+		return false
+	}
+	return true
+}
+
+func (pool *Pool) put(client *OneShotClient) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	if pool.isClosed {
+		_ = client.Close()
+		return
+	}
+	pool.idle <- client
+}
+
+// replace closes client and starts its successor in the background.
+func (pool *Pool) replace(client *OneShotClient, died bool) {
+	_ = client.Close()
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	if died {
+		pool.stats.Replaced++
+	}
+	if pool.isClosed {
+		return
+	}
+	pool.stats.Starting++
+	pool.starts.Add(1)
+	go pool.startReplacement()
+}
+
+func (pool *Pool) startReplacement() {
+	defer pool.starts.Done()
+	for {
+		client, err := StartOneShot(pool.options)
+
+		pool.mu.Lock()
+		if err == nil {
+			pool.stats.Starting--
+			pool.stats.Started++
+			if pool.isClosed {
+				pool.mu.Unlock()
+				_ = client.Close()
+				return
+			}
+			pool.idle <- client
+			pool.mu.Unlock()
+			return
+		}
+		pool.stats.StartFailures++
+		pool.mu.Unlock()
+		pool.logger.Warn("pool start failed", "error", err)
+
+		select {
+		case <-pool.closed:
+			pool.mu.Lock()
+			pool.stats.Starting--
+			pool.mu.Unlock()
+			return
+		case <-time.After(defaultPoolRetryDelay):
+		}
+	}
+}
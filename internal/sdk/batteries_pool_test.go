@@ -0,0 +1,38 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/joshp123/pi-golang/internal/testsupport"
+)
+
+func TestPoolAcquireRejectsClientAfterConcurrentClose(t *testing.T) {
+	testsupport.SetupFakePI(t, "happy")
+
+	pool, err := NewPool(testModelOneShotOptions(), PoolOptions{Size: 1})
+	if err != nil {
+		t.Fatalf("NewPool returned error: %v", err)
+	}
+	idle := <-pool.idle
+	pool.idle <- idle
+
+	// Close has marked the pool closed but not yet closed the channel, so
+	// acquire passes its first check and can still receive from idle.
+	pool.mu.Lock()
+	pool.isClosed = true
+	pool.mu.Unlock()
+
+	client, err := pool.acquire(context.Background())
+	if !errors.Is(err, ErrClientClosed) || client != nil {
+		t.Fatalf("expected ErrClientClosed, got client %v, err %v", client, err)
+	}
+	if !idle.isClosed() {
+		t.Fatal("expected the received client to be closed")
+	}
+	if stats := pool.Stats(); stats.InUse != 0 {
+		t.Fatalf("expected no borrowed clients, got %+v", stats)
+	}
+	close(pool.closed)
+}
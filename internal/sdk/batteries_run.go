@@ -2,13 +2,17 @@ package sdk
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/joshp123/pi-golang/internal/rpc"
 )
 
-var defaultRunAbortTimeout = 2 * time.Second
+var (
+	defaultRunAbortTimeout = 2 * time.Second
+	defaultRunAbortGrace   = 5 * time.Second
+)
 
 // Batteries layer: higher-level helpers built on top of thin RPC methods.
 //
@@ -26,100 +30,235 @@ func (client *Client) Run(ctx context.Context, request PromptRequest) (RunResult
 }
 
 func (client *Client) RunDetailed(ctx context.Context, request PromptRequest) (RunDetailedResult, error) {
-	if ctx == nil {
-		return RunDetailedResult{}, ErrNilContext
-	}
-	if !client.runInProgress.CompareAndSwap(false, true) {
-		return RunDetailedResult{}, ErrRunInProgress
+	return client.RunDetailedWithOptions(ctx, request, RunOptions{})
+}
+
+// RunDetailedWithOptions is RunDetailed with per-run limits. A tripped Budget
+// aborts the run and returns *BudgetExceededError (errors.Is ErrBudgetExceeded).
+func (client *Client) RunDetailedWithOptions(ctx context.Context, request PromptRequest, options RunOptions) (RunDetailedResult, error) {
+	release, queueWait, err := client.acquireRun(ctx)
+	if err != nil {
+		return RunDetailedResult{}, err
 	}
-	defer client.runInProgress.Store(false)
+	defer release()
+
+	statsBefore, statsOK := client.sessionStatsBestEffort(ctx)
 
-	events, cancel, err := client.Subscribe(SubscriptionPolicy{Buffer: 256, Mode: SubscriptionModeRing})
+	startedAt := time.Now()
+	events, cancel, promptRequestID, err := client.startPrompt(ctx, request, SubscriptionPolicy{Buffer: 256, Mode: SubscriptionModeRing})
 	if err != nil {
 		return RunDetailedResult{}, err
 	}
 	defer cancel()
 
+	promptAck := time.Since(startedAt)
+
+	result, err := client.waitForRunDetailed(ctx, events, promptRequestID, startedAt, options)
+	var budgetErr *BudgetExceededError
+	var stalledErr *RunStalledError
+	if err != nil && !errors.As(err, &budgetErr) && !errors.As(err, &stalledErr) {
+		return RunDetailedResult{}, err
+	}
+	result.QueueWait = queueWait
+	result.Timing.PromptAck = promptAck
+	if statsOK {
+		if statsAfter, ok := client.sessionStatsBestEffort(ctx); ok {
+			delta := usageDelta(statsBefore.Usage, statsAfter.Usage)
+			result.UsageDelta = &delta
+		}
+	}
+	switch {
+	case budgetErr != nil:
+		budgetErr.Partial = result
+		return RunDetailedResult{}, budgetErr
+	case stalledErr != nil:
+		stalledErr.Partial = result
+		return RunDetailedResult{}, stalledErr
+	}
+	return result, nil
+}
+
+// acquireRun enforces one Run/RunDetailed/Stream at a time per client. With
+// RunQueue, callers wait FIFO (ctx- and Close-aware) instead of failing fast;
+// the returned duration is the time spent queued.
+func (client *Client) acquireRun(ctx context.Context) (func(), time.Duration, error) {
+	if ctx == nil {
+		return nil, 0, ErrNilContext
+	}
+	if !client.runQueue {
+		if !client.runLock.TryAcquire() {
+			return nil, 0, ErrRunInProgress
+		}
+		return client.runLock.Release, 0, nil
+	}
+
+	queuedAt := time.Now()
+	waitCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-client.closed:
+			cancel()
+		case <-waitCtx.Done():
+		}
+	}()
+	if err := client.runLock.Acquire(waitCtx); err != nil {
+		if ctx.Err() != nil {
+			return nil, 0, ctx.Err()
+		}
+		return nil, 0, ErrClientClosed
+	}
+	return client.runLock.Release, time.Since(queuedAt), nil
+}
+
+// RunQueueStats reports run slot occupancy; Waiting is always 0 without RunQueue.
+func (client *Client) RunQueueStats() RunQueueStats {
+	return RunQueueStats{
+		Enabled: client.runQueue,
+		Running: client.runLock.Held(),
+		Waiting: client.runLock.Waiting(),
+	}
+}
+
+// startPrompt subscribes before sending the prompt so no run event is missed.
+func (client *Client) startPrompt(ctx context.Context, request PromptRequest, policy SubscriptionPolicy) (<-chan Event, func(), string, error) {
 	command, err := promptCommand(request)
 	if err != nil {
-		return RunDetailedResult{}, err
+		return nil, nil, "", err
+	}
+	events, cancel, err := client.Subscribe(policy)
+	if err != nil {
+		return nil, nil, "", err
 	}
 	promptResponse, err := client.send(ctx, command)
 	if err != nil {
-		return RunDetailedResult{}, err
+		cancel()
+		return nil, nil, "", err
+	}
+	return events, cancel, promptResponse.ID, nil
+}
+
+func (client *Client) waitForRunDetailed(ctx context.Context, events <-chan Event, promptRequestID string, startedAt time.Time, options RunOptions) (RunDetailedResult, error) {
+	collector := newRunCollector(startedAt)
+	budget := newBudgetTracker(options.Budget, startedAt)
+	budgetCtx, cancelBudget := budget.context(ctx)
+	defer cancelBudget()
+	runCtx, watchdog, stopWatchdog := newIdleWatchdog(budgetCtx, options.IdleTimeout, client.hostCalls)
+	defer stopWatchdog()
+	grace := options.AbortGrace
+	if grace <= 0 {
+		grace = defaultRunAbortGrace
 	}
 
-	return client.waitForRunDetailed(ctx, events, promptResponse.ID)
+	for {
+		event, err := client.nextRunEvent(runCtx, events, promptRequestID)
+		if err != nil {
+			// For run limits, nextRunEvent has already sent the abort.
+			switch {
+			case budget.wallTimeExceeded(ctx, runCtx):
+				budgetErr := budget.exceededError(BudgetLimitWallTime, time.Now())
+				client.drainAbortedRun(ctx, events, promptRequestID, collector, grace)
+				return collector.result, budgetErr
+			case ctx.Err() == nil && watchdog.expired(runCtx):
+				stalledErr := &RunStalledError{IdleTimeout: options.IdleTimeout, LastEventType: watchdog.lastEventType}
+				if !client.drainAbortedRun(ctx, events, promptRequestID, collector, grace) && ctx.Err() == nil {
+					client.kill()
+					stalledErr.Killed = true
+				}
+				return collector.result, stalledErr
+			}
+			return RunDetailedResult{}, err
+		}
+		watchdog.observe(event.Type)
+		now := eventTime(event)
+		done, err := collector.observe(event, now)
+		if err != nil {
+			return RunDetailedResult{}, err
+		}
+		if done {
+			return collector.result, nil
+		}
+		if limit, exceeded := budget.observe(event); exceeded {
+			budgetErr := budget.exceededError(limit, now)
+			client.abortRunBestEffort()
+			client.drainAbortedRun(ctx, events, promptRequestID, collector, grace)
+			return collector.result, budgetErr
+		}
+	}
+}
+
+// drainAbortedRun keeps collecting after an SDK-initiated abort so the result
+// carries the aborted agent_end, and reports whether it arrived within grace.
+func (client *Client) drainAbortedRun(ctx context.Context, events <-chan Event, promptRequestID string, collector *runCollector, grace time.Duration) bool {
+	timer := time.NewTimer(grace)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-timer.C:
+			return false
+		case event, ok := <-events:
+			if !ok || event.Type == EventTypeProcessDied {
+				return false
+			}
+			if _, handled := asyncPromptFailure(event, promptRequestID); handled {
+				continue
+			}
+			done, err := collector.observe(event, eventTime(event))
+			if err != nil {
+				return false
+			}
+			if done {
+				return true
+			}
+		}
+	}
 }
 
-func (client *Client) waitForRunDetailed(ctx context.Context, events <-chan Event, promptRequestID string) (RunDetailedResult, error) {
-	result := RunDetailedResult{}
+// nextRunEvent returns the next event of the active run. It aborts the run on ctx
+// cancellation and turns process death, stream closure, and late async prompt
+// failures into errors.
+func (client *Client) nextRunEvent(ctx context.Context, events <-chan Event, promptRequestID string) (Event, error) {
 	for {
 		select {
 		case <-ctx.Done():
 			client.abortRunBestEffort()
-			return RunDetailedResult{}, ctx.Err()
+			return Event{}, ctx.Err()
 		case event, ok := <-events:
 			if !ok {
 				if err := client.terminalError(); err != nil {
-					return RunDetailedResult{}, err
+					return Event{}, err
 				}
-				return RunDetailedResult{}, fmt.Errorf("%w: event stream closed", ErrProtocolViolation)
+				return Event{}, fmt.Errorf("%w: event stream closed", ErrProtocolViolation)
 			}
 
 			if event.Type == EventTypeProcessDied {
 				if err := client.currentProcessError(); err != nil {
-					return RunDetailedResult{}, err
+					return Event{}, err
 				}
-				return RunDetailedResult{}, ErrProcessDied
+				return Event{}, ErrProcessDied
 			}
 
 			if err, handled := asyncPromptFailure(event, promptRequestID); handled {
 				if err != nil {
-					return RunDetailedResult{}, err
+					return Event{}, err
 				}
 				continue
 			}
-
-			switch event.Type {
-			case EventTypeAutoCompactionStart:
-				parsed, err := DecodeAutoCompactionStart(event.Raw)
-				if err == nil {
-					result.AutoCompactionStart = &parsed
-				}
-				continue
-			case EventTypeAutoCompactionEnd:
-				parsed, err := DecodeAutoCompactionEnd(event.Raw)
-				if err == nil {
-					result.AutoCompactionEnd = &parsed
-				}
-				continue
-			case EventTypeAutoRetryStart:
-				parsed, err := DecodeAutoRetryStart(event.Raw)
-				if err == nil {
-					result.AutoRetryStart = &parsed
-				}
-				continue
-			case EventTypeAutoRetryEnd:
-				parsed, err := DecodeAutoRetryEnd(event.Raw)
-				if err == nil {
-					result.AutoRetryEnd = &parsed
-				}
-				continue
-			case EventTypeAgentEnd:
-				outcome, err := DecodeTerminalOutcome(event.Raw)
-				if err != nil {
-					return RunDetailedResult{}, err
-				}
-				result.Outcome = outcome
-				return result, nil
-			default:
-				continue
-			}
+			return event, nil
 		}
 	}
 }
 
+// eventTime is when the event was received, or now for events built without a stamp.
+func eventTime(event Event) time.Time {
+	if event.ReceivedAt.IsZero() {
+		return time.Now()
+	}
+	return event.ReceivedAt
+}
+
 func asyncPromptFailure(event Event, promptRequestID string) (error, bool) {
 	if event.Type != rpc.EventResponse || promptRequestID == "" {
 		return nil, false
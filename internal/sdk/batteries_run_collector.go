@@ -0,0 +1,214 @@
+package sdk
+
+import "time"
+
+// runCollector folds streamed run events into one RunDetailedResult.
+//
+// Informational events that fail to decode are skipped (the run itself is not
+// affected); an undecodable agent_end is fatal because it carries the outcome.
+type runCollector struct {
+	result     RunDetailedResult
+	startedAt  time.Time
+	toolIndex  map[string]int
+	toolStarts map[string]time.Time
+	// assistantStart/End bound the latest assistant message for tokens/sec.
+	assistantStart time.Time
+	assistantEnd   time.Time
+}
+
+func newRunCollector(startedAt time.Time) *runCollector {
+	return &runCollector{
+		startedAt:  startedAt,
+		toolIndex:  map[string]int{},
+		toolStarts: map[string]time.Time{},
+	}
+}
+
+// observe records one event and reports whether agent_end completed the run.
+func (collector *runCollector) observe(event Event, now time.Time) (bool, error) {
+	switch event.Type {
+	case EventTypeAutoCompactionStart:
+		parsed, err := DecodeAutoCompactionStart(event.Raw)
+		if err == nil {
+			collector.result.AutoCompactionStart = &parsed
+			collector.result.Compactions = append(collector.result.Compactions, CompactionEpisode{Start: parsed, StartedAt: now.Sub(collector.startedAt)})
+		}
+	case EventTypeAutoCompactionEnd:
+		parsed, err := DecodeAutoCompactionEnd(event.Raw)
+		if err == nil {
+			collector.result.AutoCompactionEnd = &parsed
+			collector.compactionEnded(parsed, now)
+		}
+	case EventTypeAutoRetryStart:
+		parsed, err := DecodeAutoRetryStart(event.Raw)
+		if err == nil {
+			collector.result.AutoRetryStart = &parsed
+			collector.result.Retries = append(collector.result.Retries, RetryAttempt{Start: parsed, StartedAt: now.Sub(collector.startedAt)})
+		}
+	case EventTypeAutoRetryEnd:
+		parsed, err := DecodeAutoRetryEnd(event.Raw)
+		if err == nil {
+			collector.result.AutoRetryEnd = &parsed
+			collector.retryEnded(parsed, now)
+		}
+	case EventTypeToolExecutionStart:
+		parsed, err := DecodeToolExecutionStart(event.Raw)
+		if err == nil {
+			collector.toolStarted(parsed, now)
+		}
+		if collector.result.Timing.FirstToolCall == 0 {
+			collector.result.Timing.FirstToolCall = now.Sub(collector.startedAt)
+		}
+	case EventTypeMessageStart:
+		parsed, err := DecodeMessageStart(event.Raw)
+		if err == nil && parsed.Message.Role == "assistant" {
+			collector.assistantStart, collector.assistantEnd = now, time.Time{}
+		}
+	case EventTypeMessageUpdate:
+		if collector.result.Timing.FirstTextDelta != 0 {
+			break
+		}
+		parsed, err := DecodeMessageUpdate(event.Raw)
+		if err == nil && parsed.AssistantMessageEvent.Type == "text_delta" {
+			collector.result.Timing.FirstTextDelta = now.Sub(collector.startedAt)
+		}
+	case EventTypeMessageEnd:
+		parsed, err := DecodeMessageEnd(event.Raw)
+		if err == nil && parsed.Message.Role == "assistant" {
+			collector.assistantEnd = now
+		}
+	case EventTypeToolExecutionUpdate:
+		parsed, err := DecodeToolExecutionUpdate(event.Raw)
+		if err == nil {
+			collector.toolUpdated(parsed)
+		}
+	case EventTypeToolExecutionEnd:
+		parsed, err := DecodeToolExecutionEnd(event.Raw)
+		if err == nil {
+			collector.toolEnded(parsed, now)
+		}
+	case EventTypeToolCallDenied:
+		parsed, err := DecodeToolCallDenied(event.Raw)
+		if err == nil {
+			collector.result.Denials = append(collector.result.Denials, parsed)
+		}
+	case EventTypeTurnEnd:
+		parsed, err := DecodeTurnEnd(event.Raw)
+		if err == nil {
+			collector.turnEnded(parsed)
+		}
+	case EventTypeAgentEnd:
+		outcome, err := DecodeTerminalOutcome(event.Raw)
+		if err != nil {
+			return false, err
+		}
+		collector.result.Outcome = outcome
+		collector.finishTiming(now)
+		return true, nil
+	}
+	return false, nil
+}
+
+func (collector *runCollector) finishTiming(now time.Time) {
+	timing := &collector.result.Timing
+	timing.Total = now.Sub(collector.startedAt)
+	for _, episode := range collector.result.Compactions {
+		if episode.End != nil {
+			timing.Compaction += episode.EndedAt - episode.StartedAt
+		}
+	}
+	for _, attempt := range collector.result.Retries {
+		timing.RetryDelay += time.Duration(attempt.Start.DelayMS) * time.Millisecond
+	}
+
+	usage := collector.result.Outcome.Usage
+	if usage == nil || usage.Output <= 0 || collector.assistantStart.IsZero() {
+		return
+	}
+	end := collector.assistantEnd
+	if end.IsZero() {
+		end = now
+	}
+	if streaming := end.Sub(collector.assistantStart); streaming > 0 {
+		timing.OutputTokensPerSecond = float64(usage.Output) / streaming.Seconds()
+	}
+}
+
+// compactionEnded closes the open episode, or records an end-only episode.
+func (collector *runCollector) compactionEnded(event AutoCompactionEndEvent, now time.Time) {
+	offset := now.Sub(collector.startedAt)
+	episodes := collector.result.Compactions
+	if len(episodes) == 0 || episodes[len(episodes)-1].End != nil {
+		collector.result.Compactions = append(episodes, CompactionEpisode{StartedAt: offset})
+	}
+	episode := &collector.result.Compactions[len(collector.result.Compactions)-1]
+	episode.End = &event
+	episode.EndedAt = offset
+}
+
+// retryEnded closes the latest attempt, or records an end-only attempt.
+func (collector *runCollector) retryEnded(event AutoRetryEndEvent, now time.Time) {
+	offset := now.Sub(collector.startedAt)
+	attempts := collector.result.Retries
+	if len(attempts) == 0 || attempts[len(attempts)-1].End != nil {
+		collector.result.Retries = append(attempts, RetryAttempt{Start: AutoRetryStartEvent{Attempt: event.Attempt}, StartedAt: offset})
+	}
+	attempt := &collector.result.Retries[len(collector.result.Retries)-1]
+	attempt.End = &event
+	attempt.EndedAt = offset
+}
+
+func (collector *runCollector) turnEnded(event TurnEndEvent) {
+	if usage := event.Message.Usage; usage != nil {
+		if collector.result.UsageDelta == nil {
+			collector.result.UsageDelta = &Usage{}
+		}
+		addTurnUsage(collector.result.UsageDelta, *usage)
+	}
+	collector.result.Turns = append(collector.result.Turns, TurnSummary{
+		Index:       len(collector.result.Turns),
+		Message:     event.Message,
+		ToolResults: event.ToolResults,
+		Usage:       event.Message.Usage,
+	})
+}
+
+func (collector *runCollector) toolStarted(event ToolExecutionStartEvent, now time.Time) {
+	call := collector.toolCall(event.ToolCallID, event.ToolName)
+	call.Args = event.Args
+	collector.toolStarts[event.ToolCallID] = now
+}
+
+func (collector *runCollector) toolUpdated(event ToolExecutionUpdateEvent) {
+	call := collector.toolCall(event.ToolCallID, event.ToolName)
+	if len(call.Args) == 0 {
+		call.Args = event.Args
+	}
+	call.PartialResult = event.PartialResult
+}
+
+func (collector *runCollector) toolEnded(event ToolExecutionEndEvent, now time.Time) {
+	call := collector.toolCall(event.ToolCallID, event.ToolName)
+	call.Result = event.Result
+	call.IsError = event.IsError
+	call.Completed = true
+	if startedAt, ok := collector.toolStarts[event.ToolCallID]; ok {
+		call.Duration = now.Sub(startedAt)
+		delete(collector.toolStarts, event.ToolCallID)
+	}
+}
+
+// toolCall returns the timeline entry for toolCallID, appending one in first-seen order.
+func (collector *runCollector) toolCall(toolCallID string, toolName string) *ToolExecution {
+	index, ok := collector.toolIndex[toolCallID]
+	if !ok {
+		index = len(collector.result.ToolCalls)
+		collector.toolIndex[toolCallID] = index
+		collector.result.ToolCalls = append(collector.result.ToolCalls, ToolExecution{ToolCallID: toolCallID})
+	}
+	call := &collector.result.ToolCalls[index]
+	if call.ToolName == "" {
+		call.ToolName = toolName
+	}
+	return call
+}
@@ -0,0 +1,104 @@
+package sdk
+
+import (
+	"context"
+	"iter"
+)
+
+// Stream mechanics:
+//  1. Acquire the run slot (shared with Run/RunDetailed) and send one prompt.
+//  2. Yield deltas and tool boundaries as they arrive, then the final outcome.
+//  3. On ctx cancellation or early break, best-effort abort the run.
+//
+// The stream subscribes in block mode so no delta is dropped; a slow consumer
+// delays other subscribers rather than losing text.
+
+func (client *Client) Stream(ctx context.Context, request PromptRequest) iter.Seq2[StreamChunk, error] {
+	return func(yield func(StreamChunk, error) bool) {
+		release, _, err := client.acquireRun(ctx)
+		if err != nil {
+			yield(StreamChunk{}, err)
+			return
+		}
+		defer release()
+
+		events, cancel, promptRequestID, err := client.startPrompt(ctx, request, SubscriptionPolicy{Buffer: 256, Mode: SubscriptionModeBlock})
+		if err != nil {
+			yield(StreamChunk{}, err)
+			return
+		}
+		defer cancel()
+
+		for {
+			event, err := client.nextRunEvent(ctx, events, promptRequestID)
+			if err != nil {
+				yield(StreamChunk{}, err)
+				return
+			}
+			chunk, ok, err := streamChunkFromEvent(event)
+			if err != nil {
+				yield(StreamChunk{}, err)
+				return
+			}
+			if !ok {
+				continue
+			}
+			if !yield(chunk, nil) {
+				if chunk.Kind != StreamChunkOutcome {
+					cancel()
+					client.abortRunBestEffort()
+				}
+				return
+			}
+			if chunk.Kind == StreamChunkOutcome {
+				return
+			}
+		}
+	}
+}
+
+// streamChunkFromEvent maps one run event to a chunk. Informational events that
+// fail to decode are skipped; an undecodable agent_end is fatal.
+func streamChunkFromEvent(event Event) (StreamChunk, bool, error) {
+	switch event.Type {
+	case EventTypeMessageUpdate:
+		parsed, err := DecodeMessageUpdate(event.Raw)
+		if err != nil {
+			return StreamChunk{}, false, nil
+		}
+		switch kind := StreamChunkKind(parsed.AssistantMessageEvent.Type); kind {
+		case StreamChunkTextDelta, StreamChunkThinkingDelta:
+			return StreamChunk{Kind: kind, Delta: parsed.AssistantMessageEvent.Delta}, true, nil
+		}
+	case EventTypeToolExecutionStart:
+		parsed, err := DecodeToolExecutionStart(event.Raw)
+		if err != nil {
+			return StreamChunk{}, false, nil
+		}
+		return StreamChunk{
+			Kind:       StreamChunkToolStart,
+			ToolCallID: parsed.ToolCallID,
+			ToolName:   parsed.ToolName,
+			Args:       parsed.Args,
+		}, true, nil
+	case EventTypeToolExecutionEnd:
+		parsed, err := DecodeToolExecutionEnd(event.Raw)
+		if err != nil {
+			return StreamChunk{}, false, nil
+		}
+		return StreamChunk{
+			Kind:       StreamChunkToolEnd,
+			ToolCallID: parsed.ToolCallID,
+			ToolName:   parsed.ToolName,
+			Result:     parsed.Result,
+			IsError:    parsed.IsError,
+		}, true, nil
+	case EventTypeAgentEnd:
+		outcome, err := DecodeTerminalOutcome(event.Raw)
+		if err != nil {
+			return StreamChunk{}, false, err
+		}
+		return StreamChunk{Kind: StreamChunkOutcome, Outcome: &outcome}, true, nil
+	}
+	return StreamChunk{}, false, nil
+}
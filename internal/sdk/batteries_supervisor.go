@@ -0,0 +1,409 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/joshp123/pi-golang/internal/stream"
+)
+
+// Supervisor mechanics:
+//  1. Start one SessionClient and forward its events into a supervisor-owned hub,
+//     so subscribers outlive any single process.
+//  2. When the process dies, restart it with the same SessionOptions, resuming
+//     the last known session file (--session), after exponential backoff.
+//  3. Publish process_restarted once the new process is up. More than MaxRestarts
+//     restarts within RestartWindow is a crash loop: give up with ErrCrashLoop.
+//
+// A run in flight when the process dies fails with ErrProcessDied; it is not
+// replayed (the prompt may already have had side effects). A caller closing the
+// client returned by Current is not a crash: it is replaced immediately, without
+// backoff and without counting toward MaxRestarts.
+
+var (
+	defaultSupervisorMaxRestarts    = 5
+	defaultSupervisorRestartWindow  = time.Minute
+	defaultSupervisorInitialBackoff = 200 * time.Millisecond
+	defaultSupervisorMaxBackoff     = 10 * time.Second
+	supervisorStateTimeout          = 5 * time.Second
+)
+
+type SupervisorOptions struct {
+	// MaxRestarts bounds restarts within RestartWindow before giving up with
+	// ErrCrashLoop (default 5).
+	MaxRestarts   int
+	RestartWindow time.Duration
+	// InitialBackoff is the delay before the first restart in a window; it doubles
+	// per further restart, capped at MaxBackoff (defaults 200ms / 10s).
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+type SupervisorStats struct {
+	Restarts int
+	// SessionFile is the session resumed on the next restart ("" until known).
+	SessionFile string
+	// Running is false while a restart is pending and after the supervisor stops.
+	Running bool
+}
+
+// Supervisor keeps a SessionClient alive across process crashes. Safe for concurrent use.
+type Supervisor struct {
+	options     SessionOptions
+	supervision SupervisorOptions
+	events      *stream.Hub[Event]
+	closed      chan struct{}
+	done        chan struct{}
+	logger      *slog.Logger
+
+	mu          sync.Mutex
+	client      *SessionClient
+	changed     chan struct{}
+	sessionFile string
+	restarts    []time.Time
+	restarted   int
+	err         error
+	isClosed    bool
+}
+
+func StartSupervisor(options SessionOptions, supervisorOptions SupervisorOptions) (*Supervisor, error) {
+	supervision, err := normalizeSupervisorOptions(supervisorOptions)
+	if err != nil {
+		return nil, err
+	}
+	client, err := StartSession(options)
+	if err != nil {
+		return nil, err
+	}
+	events, cancel, err := client.Subscribe(SubscriptionPolicy{Buffer: 256, Mode: SubscriptionModeBlock})
+	if err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+
+	supervisor := &Supervisor{
+		options:     options,
+		supervision: supervision,
+		events:      newEventHub(),
+		closed:      make(chan struct{}),
+		done:        make(chan struct{}),
+		logger:      resolveLogger(options.Logger),
+		client:      client,
+		changed:     make(chan struct{}),
+		sessionFile: options.SessionName,
+	}
+	supervisor.watchSessionChanges(client)
+	supervisor.refreshSessionFile(client)
+	go supervisor.supervise(client, events, cancel)
+	return supervisor, nil
+}
+
+func normalizeSupervisorOptions(options SupervisorOptions) (SupervisorOptions, error) {
+	if options.MaxRestarts < 0 || options.RestartWindow < 0 || options.InitialBackoff < 0 || options.MaxBackoff < 0 {
+		return SupervisorOptions{}, errors.New("supervisor options must be >= 0")
+	}
+	if options.MaxRestarts == 0 {
+		options.MaxRestarts = defaultSupervisorMaxRestarts
+	}
+	if options.RestartWindow == 0 {
+		options.RestartWindow = defaultSupervisorRestartWindow
+	}
+	if options.InitialBackoff == 0 {
+		options.InitialBackoff = defaultSupervisorInitialBackoff
+	}
+	if options.MaxBackoff == 0 {
+		options.MaxBackoff = defaultSupervisorMaxBackoff
+	}
+	return options, nil
+}
+
+// Current returns the live client, waiting (honoring ctx) while a restart is
+// pending. Do not cache it across calls: it is replaced after every crash, and
+// after a caller closes it.
+func (supervisor *Supervisor) Current(ctx context.Context) (*SessionClient, error) {
+	if ctx == nil {
+		return nil, ErrNilContext
+	}
+	for {
+		supervisor.mu.Lock()
+		client, changed, err := supervisor.client, supervisor.changed, supervisor.terminalErrorLocked()
+		supervisor.mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+		if client != nil && client.currentProcessError() == nil {
+			return client, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+func (supervisor *Supervisor) Run(ctx context.Context, request PromptRequest) (RunResult, error) {
+	client, err := supervisor.Current(ctx)
+	if err != nil {
+		return RunResult{}, err
+	}
+	return client.Run(ctx, request)
+}
+
+func (supervisor *Supervisor) RunDetailed(ctx context.Context, request PromptRequest) (RunDetailedResult, error) {
+	return supervisor.RunDetailedWithOptions(ctx, request, RunOptions{})
+}
+
+func (supervisor *Supervisor) RunDetailedWithOptions(ctx context.Context, request PromptRequest, options RunOptions) (RunDetailedResult, error) {
+	client, err := supervisor.Current(ctx)
+	if err != nil {
+		return RunDetailedResult{}, err
+	}
+	return client.RunDetailedWithOptions(ctx, request, options)
+}
+
+// Subscribe receives events from every process generation plus process_restarted.
+// The channel closes on Close, or after a final process_died on ErrCrashLoop.
+func (supervisor *Supervisor) Subscribe(policy SubscriptionPolicy) (<-chan Event, func(), error) {
+	if err := supervisor.Err(); err != nil {
+		return nil, nil, err
+	}
+	if err := validateSubscriptionPolicy(policy); err != nil {
+		return nil, nil, err
+	}
+	return supervisor.events.Subscribe(toStreamPolicy(policy))
+}
+
+// Err reports why the supervisor stopped (ErrClientClosed or ErrCrashLoop), or nil.
+func (supervisor *Supervisor) Err() error {
+	supervisor.mu.Lock()
+	defer supervisor.mu.Unlock()
+	return supervisor.terminalErrorLocked()
+}
+
+func (supervisor *Supervisor) Stats() SupervisorStats {
+	supervisor.mu.Lock()
+	defer supervisor.mu.Unlock()
+	return SupervisorStats{
+		Restarts:    supervisor.restarted,
+		SessionFile: supervisor.sessionFile,
+		Running:     supervisor.client != nil && supervisor.terminalErrorLocked() == nil,
+	}
+}
+
+func (supervisor *Supervisor) Close() error {
+	supervisor.mu.Lock()
+	if supervisor.isClosed {
+		supervisor.mu.Unlock()
+		return nil
+	}
+	supervisor.isClosed = true
+	close(supervisor.closed)
+	client := supervisor.client
+	supervisor.notifyLocked()
+	supervisor.mu.Unlock()
+
+	supervisor.events.Close()
+	if client != nil {
+		_ = client.Close()
+	}
+	<-supervisor.done
+	return nil
+}
+
+func (supervisor *Supervisor) terminalErrorLocked() error {
+	if supervisor.isClosed {
+		return ErrClientClosed
+	}
+	return supervisor.err
+}
+
+func (supervisor *Supervisor) notifyLocked() {
+	close(supervisor.changed)
+	supervisor.changed = make(chan struct{})
+}
+
+func (supervisor *Supervisor) supervise(client *SessionClient, events <-chan Event, cancel func()) {
+	defer close(supervisor.done)
+	for {
+		supervisor.forward(client, events)
+		cancel()
+		cause := client.currentProcessError()
+		crashed := !client.isClosed()
+		if !crashed {
+			cause = ErrClientClosed
+		}
+		_ = client.Close()
+
+		supervisor.mu.Lock()
+		if supervisor.isClosed {
+			supervisor.mu.Unlock()
+			return
+		}
+		supervisor.client = nil
+		supervisor.notifyLocked()
+		supervisor.mu.Unlock()
+
+		var err error
+		client, events, cancel, err = supervisor.restart(cause, crashed)
+		if errors.Is(err, ErrClientClosed) {
+			return
+		}
+		if err != nil {
+			supervisor.mu.Lock()
+			supervisor.err = err
+			supervisor.notifyLocked()
+			supervisor.mu.Unlock()
+			supervisor.logger.Error("supervisor giving up", "error", err)
+			supervisor.events.ProcessDied(newProcessDiedEvent(err))
+			return
+		}
+	}
+}
+
+// forward relays events until the client's stream closes. process_died is
+// withheld: subscribers see process_restarted instead. Session file refreshes
+// run on a side goroutine (coalesced) so get_state never stalls the relay.
+func (supervisor *Supervisor) forward(client *SessionClient, events <-chan Event) {
+	refresh := make(chan struct{}, 1)
+	defer close(refresh)
+	go func() {
+		for range refresh {
+			supervisor.refreshSessionFile(client)
+		}
+	}()
+
+	for event := range events {
+		if event.Type == EventTypeProcessDied {
+			continue
+		}
+		if event.Type == EventTypeAgentEnd {
+			select {
+			case refresh <- struct{}{}:
+			default:
+			}
+		}
+		supervisor.events.Publish(event)
+	}
+}
+
+// restart starts the next process. Only crashes count toward MaxRestarts and
+// back off; replacing a caller-closed client does neither.
+func (supervisor *Supervisor) restart(cause error, crashed bool) (*SessionClient, <-chan Event, func(), error) {
+	for {
+		supervisor.mu.Lock()
+		now := time.Now()
+		recent := supervisor.restarts[:0]
+		for _, at := range supervisor.restarts {
+			if now.Sub(at) < supervisor.supervision.RestartWindow {
+				recent = append(recent, at)
+			}
+		}
+		supervisor.restarts = recent
+		var delay time.Duration
+		if crashed {
+			if len(recent) >= supervisor.supervision.MaxRestarts {
+				supervisor.mu.Unlock()
+				return nil, nil, nil, fmt.Errorf("%w: %d restarts within %s, last cause: %v", ErrCrashLoop, len(recent), supervisor.supervision.RestartWindow, cause)
+			}
+			delay = supervisor.backoff(len(recent))
+			supervisor.restarts = append(supervisor.restarts, now)
+		}
+		options := supervisor.options
+		options.SessionName = supervisor.sessionFile
+		supervisor.mu.Unlock()
+
+		supervisor.logger.Warn("restarting pi process", "backoff", delay, "session_file", options.SessionName, "cause", cause)
+		select {
+		case <-supervisor.closed:
+			return nil, nil, nil, ErrClientClosed
+		case <-time.After(delay):
+		}
+
+		client, err := StartSession(options)
+		if err != nil {
+			supervisor.logger.Warn("supervisor restart failed", "error", err)
+			cause = err
+			crashed = true
+			continue
+		}
+		events, cancel, err := client.Subscribe(SubscriptionPolicy{Buffer: 256, Mode: SubscriptionModeBlock})
+		if err != nil {
+			_ = client.Close()
+			cause = err
+			crashed = true
+			continue
+		}
+		supervisor.watchSessionChanges(client)
+
+		supervisor.mu.Lock()
+		if supervisor.isClosed {
+			supervisor.mu.Unlock()
+			cancel()
+			_ = client.Close()
+			return nil, nil, nil, ErrClientClosed
+		}
+		supervisor.client = client
+		supervisor.restarted++
+		restarted := supervisor.restarted
+		supervisor.notifyLocked()
+		supervisor.mu.Unlock()
+
+		supervisor.refreshSessionFile(client)
+		supervisor.logger.Info("pi process restarted", "restart", restarted)
+		supervisor.events.Publish(newProcessRestartedEvent(restarted, supervisor.Stats().SessionFile, cause))
+		return client, events, cancel, nil
+	}
+}
+
+func (supervisor *Supervisor) backoff(recentRestarts int) time.Duration {
+	delay := supervisor.supervision.InitialBackoff
+	for range recentRestarts {
+		delay *= 2
+		if delay >= supervisor.supervision.MaxBackoff {
+			return supervisor.supervision.MaxBackoff
+		}
+	}
+	return min(delay, supervisor.supervision.MaxBackoff)
+}
+
+// watchSessionChanges refreshes the session file as soon as a caller moves the
+// client to another session (NewSession, SwitchSession, Fork), so a crash
+// before the next agent_end still resumes the right conversation.
+func (supervisor *Supervisor) watchSessionChanges(client *SessionClient) {
+	client.sessionChanged = func() { supervisor.refreshSessionFile(client) }
+}
+
+// refreshSessionFile records the session to resume. Best effort: a failed
+// get_state keeps the previous value.
+func (supervisor *Supervisor) refreshSessionFile(client *SessionClient) {
+	ctx, cancel := context.WithTimeout(context.Background(), supervisorStateTimeout)
+	defer cancel()
+	state, err := client.GetState(ctx)
+	if err != nil || state.SessionFile == "" {
+		return
+	}
+	supervisor.mu.Lock()
+	supervisor.sessionFile = state.SessionFile
+	supervisor.mu.Unlock()
+}
+
+func newProcessRestartedEvent(restart int, sessionFile string, cause error) Event {
+	payload := map[string]any{
+		"type":    EventTypeProcessRestarted,
+		"restart": restart,
+	}
+	if sessionFile != "" {
+		payload["sessionFile"] = sessionFile
+	}
+	if cause != nil {
+		payload["error"] = cause.Error()
+	}
+	raw, _ := json.Marshal(payload)
+	return Event{Type: EventTypeProcessRestarted, Raw: raw, ReceivedAt: time.Now()}
+}
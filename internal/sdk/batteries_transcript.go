@@ -0,0 +1,189 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	messageRoleUser       = "user"
+	messageRoleAssistant  = "assistant"
+	messageRoleToolResult = "toolResult"
+	// messageRoleBashExecution is a user-run Bash command recorded in the session.
+	messageRoleBashExecution = "bashExecution"
+)
+
+// Transcript is a decoded conversation that renders to plain text or Markdown for audit logs.
+type Transcript struct {
+	Entries []TranscriptEntry
+}
+
+type TranscriptEntry struct {
+	Message AgentMessage
+	Blocks  []ContentBlock
+}
+
+// NewTranscript decodes the content blocks of every message.
+func NewTranscript(messages []AgentMessage) (Transcript, error) {
+	entries := make([]TranscriptEntry, 0, len(messages))
+	for index, message := range messages {
+		blocks, err := message.Blocks()
+		if err != nil {
+			return Transcript{}, fmt.Errorf("decode message %d (%s): %w", index, message.Role, err)
+		}
+		entries = append(entries, TranscriptEntry{Message: message, Blocks: blocks})
+	}
+	return Transcript{Entries: entries}, nil
+}
+
+// GetTranscript fetches the session conversation with GetMessages and decodes it.
+func (client *Client) GetTranscript(ctx context.Context) (Transcript, error) {
+	messages, err := client.GetMessages(ctx)
+	if err != nil {
+		return Transcript{}, err
+	}
+	return NewTranscript(messages)
+}
+
+// Text renders one section per message; messages with nothing to show (no
+// content, error, or shell command) are skipped.
+func (transcript Transcript) Text() string {
+	sections := make([]string, 0, len(transcript.Entries))
+	for _, entry := range transcript.Entries {
+		if !entry.renderable() {
+			continue
+		}
+		lines := []string{transcriptHeading(entry.Message, false) + ":"}
+		if entry.Message.Role == messageRoleBashExecution {
+			lines = append(lines, "$ "+entry.Message.Command)
+			if output := strings.TrimSuffix(entry.Message.Output, "\n"); output != "" {
+				lines = append(lines, output)
+			}
+		}
+		for _, block := range entry.Blocks {
+			lines = append(lines, textBlockLine(block))
+		}
+		if entry.Message.ErrorMessage != "" {
+			lines = append(lines, "[error] "+entry.Message.ErrorMessage)
+		}
+		sections = append(sections, strings.Join(lines, "\n"))
+	}
+	return joinSections(sections)
+}
+
+// Markdown renders like Text, with shell commands and tool output fenced.
+func (transcript Transcript) Markdown() string {
+	sections := make([]string, 0, len(transcript.Entries))
+	for _, entry := range transcript.Entries {
+		if !entry.renderable() {
+			continue
+		}
+		parts := []string{"## " + transcriptHeading(entry.Message, true)}
+		if entry.Message.Role == messageRoleBashExecution {
+			parts = append(parts, fencedMarkdown("console", strings.TrimSuffix("$ "+entry.Message.Command+"\n"+entry.Message.Output, "\n")))
+		}
+		for _, block := range entry.Blocks {
+			parts = append(parts, markdownBlock(entry.Message, block))
+		}
+		if entry.Message.ErrorMessage != "" {
+			parts = append(parts, "**Error:** "+entry.Message.ErrorMessage)
+		}
+		sections = append(sections, strings.Join(parts, "\n\n"))
+	}
+	return joinSections(sections)
+}
+
+func (entry TranscriptEntry) renderable() bool {
+	return len(entry.Blocks) > 0 || entry.Message.ErrorMessage != "" || entry.Message.Role == messageRoleBashExecution
+}
+
+func transcriptHeading(message AgentMessage, markdown bool) string {
+	var heading string
+	switch message.Role {
+	case messageRoleUser:
+		heading = "User"
+	case messageRoleAssistant:
+		heading = "Assistant"
+	case messageRoleToolResult:
+		name := message.ToolName
+		if markdown {
+			name = "`" + name + "`"
+		}
+		heading = fmt.Sprintf("Tool result %s (%s)", name, message.ToolCallID)
+		if message.IsError {
+			heading += " [error]"
+		}
+	case messageRoleBashExecution:
+		heading = "Bash"
+		if message.ExitCode != nil {
+			heading += fmt.Sprintf(" (exit %d)", *message.ExitCode)
+		}
+	default:
+		heading = message.Role
+	}
+	if message.Timestamp > 0 {
+		heading += " @ " + time.UnixMilli(message.Timestamp).UTC().Format(time.RFC3339)
+	}
+	return heading
+}
+
+func textBlockLine(block ContentBlock) string {
+	switch block := block.(type) {
+	case TextBlock:
+		return block.Text
+	case ThinkingBlock:
+		return "[thinking] " + block.Thinking
+	case ImageBlock:
+		return describeImageBlock(block)
+	case ToolCallBlock:
+		return fmt.Sprintf("[tool call %s (%s)] %s", block.Name, block.ID, string(block.Arguments))
+	default:
+		return fmt.Sprintf("[%s block]", block.BlockType())
+	}
+}
+
+func markdownBlock(message AgentMessage, block ContentBlock) string {
+	switch block := block.(type) {
+	case TextBlock:
+		if message.Role == messageRoleToolResult {
+			return fencedMarkdown("", block.Text)
+		}
+		return block.Text
+	case ThinkingBlock:
+		return "> **Thinking**\n>\n> " + strings.ReplaceAll(block.Thinking, "\n", "\n> ")
+	case ImageBlock:
+		return "_" + describeImageBlock(block) + "_"
+	case ToolCallBlock:
+		return fmt.Sprintf("**Tool call** `%s` (%s)\n\n%s", block.Name, block.ID, fencedMarkdown("json", string(block.Arguments)))
+	default:
+		return fmt.Sprintf("_[%s block]_", block.BlockType())
+	}
+}
+
+func describeImageBlock(block ImageBlock) string {
+	return fmt.Sprintf("[image %s, %d base64 bytes]", block.MIMEType, len(block.Data))
+}
+
+// fencedMarkdown picks a fence longer than any backtick run in body.
+func fencedMarkdown(language string, body string) string {
+	longest, run := 0, 0
+	for _, char := range body {
+		if char == '`' {
+			run++
+			longest = max(longest, run)
+			continue
+		}
+		run = 0
+	}
+	fence := strings.Repeat("`", max(3, longest+1))
+	return fence + language + "\n" + body + "\n" + fence
+}
+
+func joinSections(sections []string) string {
+	if len(sections) == 0 {
+		return ""
+	}
+	return strings.Join(sections, "\n\n") + "\n"
+}
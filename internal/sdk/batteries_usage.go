@@ -0,0 +1,70 @@
+package sdk
+
+import (
+	"context"
+	"time"
+)
+
+var defaultUsageStatsTimeout = 5 * time.Second
+
+// sessionStatsBestEffort fetches stats for usage accounting; failures only
+// fall back to the turn_end sum for RunDetailedResult.UsageDelta and never
+// fail the run.
+func (client *Client) sessionStatsBestEffort(ctx context.Context) (SessionStats, bool) {
+	statsCtx, cancel := context.WithTimeout(ctx, defaultUsageStatsTimeout)
+	defer cancel()
+	stats, err := client.GetSessionStats(statsCtx)
+	if err != nil {
+		client.logger.Debug("session stats unavailable", "error", err)
+		return SessionStats{}, false
+	}
+	return stats, true
+}
+
+// usageDelta subtracts cumulative session usage. Fields that shrank (e.g. the
+// session was switched or rewritten mid-run) are clamped to zero.
+func usageDelta(before Usage, after Usage) Usage {
+	delta := Usage{
+		Input:       max(after.Input-before.Input, 0),
+		Output:      max(after.Output-before.Output, 0),
+		CacheRead:   max(after.CacheRead-before.CacheRead, 0),
+		CacheWrite:  max(after.CacheWrite-before.CacheWrite, 0),
+		TotalTokens: max(after.TotalTokens-before.TotalTokens, 0),
+	}
+	if before.Cost != nil || after.Cost != nil {
+		delta.Cost = &Cost{Total: max(costTotal(after.Cost)-costTotal(before.Cost), 0)}
+	}
+	return delta
+}
+
+func costTotal(cost *Cost) float64 {
+	if cost == nil {
+		return 0
+	}
+	return cost.Total
+}
+
+// addTurnUsage folds one turn's assistant usage into the run total. Negative
+// fields are clamped to zero so a malformed turn never shrinks the total.
+func addTurnUsage(total *Usage, turn Usage) {
+	total.Input += max(turn.Input, 0)
+	total.Output += max(turn.Output, 0)
+	total.CacheRead += max(turn.CacheRead, 0)
+	total.CacheWrite += max(turn.CacheWrite, 0)
+	turnTotal := turn.TotalTokens
+	if turnTotal == 0 {
+		turnTotal = turn.Input + turn.Output + turn.CacheRead + turn.CacheWrite
+	}
+	total.TotalTokens += max(turnTotal, 0)
+	if turn.Cost == nil {
+		return
+	}
+	if total.Cost == nil {
+		total.Cost = &Cost{}
+	}
+	total.Cost.Input += max(turn.Cost.Input, 0)
+	total.Cost.Output += max(turn.Cost.Output, 0)
+	total.Cost.CacheRead += max(turn.Cost.CacheRead, 0)
+	total.Cost.CacheWrite += max(turn.Cost.CacheWrite, 0)
+	total.Cost.Total += max(turn.Cost.Total, 0)
+}
@@ -0,0 +1,78 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Idle watchdog mechanics:
+//  1. Every event the run loop receives resets a timer.
+//  2. On expiry the timer re-arms while a host call (bridged tool or approval)
+//     is outstanding, or until timeout has passed since the last one finished.
+//  3. Otherwise the run context is cancelled with errRunIdle, so nextRunEvent
+//     sends the usual best-effort abort.
+//  4. The run loop then waits AbortGrace for agent_end and kills the process
+//     if it never arrives (a hung process ignores abort too).
+
+var errRunIdle = errors.New("run idle timeout")
+
+type idleWatchdog struct {
+	timeout       time.Duration
+	calls         *hostCalls
+	lastEventType string
+
+	mu    sync.Mutex // guards timer against the expiry callback
+	timer *time.Timer
+}
+
+// newIdleWatchdog derives a context cancelled with errRunIdle once timeout
+// passes without observe or host call activity. A zero timeout disables it.
+func newIdleWatchdog(ctx context.Context, timeout time.Duration, calls *hostCalls) (context.Context, *idleWatchdog, func()) {
+	watchdog := &idleWatchdog{timeout: timeout, calls: calls}
+	if timeout <= 0 {
+		return ctx, watchdog, func() {}
+	}
+	watchCtx, cancel := context.WithCancelCause(ctx)
+	watchdog.mu.Lock()
+	watchdog.timer = time.AfterFunc(timeout, func() { watchdog.fire(watchCtx, cancel) })
+	watchdog.mu.Unlock()
+	return watchCtx, watchdog, func() {
+		watchdog.timer.Stop()
+		cancel(nil)
+	}
+}
+
+// fire re-arms the timer while host call activity covers the gap, and
+// otherwise cancels the run with errRunIdle.
+func (watchdog *idleWatchdog) fire(watchCtx context.Context, cancel context.CancelCauseFunc) {
+	if watchCtx.Err() != nil {
+		return
+	}
+	watchdog.mu.Lock()
+	defer watchdog.mu.Unlock()
+	lastDone, quiet := watchdog.calls.quietSince()
+	if !quiet {
+		watchdog.timer.Reset(watchdog.timeout)
+		return
+	}
+	if remaining := watchdog.timeout - time.Since(lastDone); remaining > 0 {
+		watchdog.timer.Reset(remaining)
+		return
+	}
+	cancel(errRunIdle)
+}
+
+func (watchdog *idleWatchdog) observe(eventType string) {
+	watchdog.lastEventType = eventType
+	if watchdog.timer != nil {
+		watchdog.timer.Reset(watchdog.timeout)
+	}
+}
+
+// expired reports whether runCtx ended because of the idle timeout rather than
+// the caller's ctx or another run limit.
+func (watchdog *idleWatchdog) expired(runCtx context.Context) bool {
+	return watchdog.timer != nil && errors.Is(context.Cause(runCtx), errRunIdle)
+}
@@ -5,8 +5,7 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"log"
-	"os"
+	"log/slog"
 	"os/exec"
 	"strings"
 	"sync"
@@ -14,37 +13,32 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/joshp123/pi-golang/internal/runtime"
 	"github.com/joshp123/pi-golang/internal/stream"
 	"github.com/joshp123/pi-golang/internal/transport"
 )
 
 var defaultShutdownTimeout = 2 * time.Second
 var startupSkillVerificationTimeout = 5 * time.Second
-
-var debugEnabledProvider = func() bool {
-	return os.Getenv("PI_DEBUG") == "1"
-}
-
-func SetDebugEnabledProvider(provider func() bool) {
-	if provider == nil {
-		debugEnabledProvider = func() bool { return false }
-		return
-	}
-	debugEnabledProvider = provider
-}
-
-func debugf(format string, args ...any) {
-	if debugEnabledProvider() {
-		log.Printf("[pi-golang] "+format, args...)
-	}
-}
+var defaultStderrLimit = 1 << 20
 
 type Client struct {
 	process *exec.Cmd
 	stdin   io.WriteCloser
 
-	stderr   bytes.Buffer
-	stderrMu sync.Mutex
+	stderr        *runtime.ByteRing
+	stderrLimit   int
+	stderrMu      sync.Mutex
+	stderrPartial []byte
+	stderrLines   *stream.Hub[StderrLine]
+	stderrWriter  io.Writer
+	stderrLogger  *slog.Logger
+
+	logger *slog.Logger
+
+	startedAt time.Time
+	// killed marks an SDK-initiated kill, reported by ProcessExitError.
+	killed atomic.Bool
 
 	requests *transport.RequestManager
 	events   *stream.Hub[Event]
@@ -63,9 +57,25 @@ type Client struct {
 	eventDispatchEnd chan struct{}
 	stopDispatchOnce sync.Once
 
-	runInProgress atomic.Bool
+	runLock  *runtime.FIFOLock
+	runQueue bool
+
+	auth ProviderAuth
 
-	managedCompactionHook *managedCompactionHook
+	uiHandler UIHandler
+	uiTimeout time.Duration
+	// uiNotices delivers notify/setStatus to uiHandler one at a time, in pi's
+	// order; dialogs are answered concurrently.
+	uiNotices *transport.Queue[ExtensionUIRequest]
+
+	tools           map[string]ToolDefinition
+	hostCalls       *hostCalls
+	approveToolCall ApproveToolCallFunc
+	extensions      []managedExtension
+
+	// sessionChanged runs after NewSession, SwitchSession or Fork moves the
+	// client to another session file. Set before the client is shared.
+	sessionChanged func()
 }
 
 type SessionClient struct {
@@ -89,6 +99,15 @@ type startConfig struct {
 	seedAuthFromHome   bool
 	skills             SkillsOptions
 	compactionPrompt   string
+	uiHandler          UIHandler
+	uiTimeout          time.Duration
+	tools              []ToolDefinition
+	approveToolCall    ApproveToolCallFunc
+	runQueue           bool
+	stderrLimit        int
+	stderrWriter       io.Writer
+	stderrLogger       *slog.Logger
+	logger             *slog.Logger
 	useSession         bool
 }
 
@@ -110,6 +129,15 @@ func StartSession(options SessionOptions) (*SessionClient, error) {
 		seedAuthFromHome:   normalized.SeedAuthFromHome,
 		skills:             normalized.Skills,
 		compactionPrompt:   normalized.CompactionPrompt,
+		uiHandler:          normalized.UIHandler,
+		uiTimeout:          normalized.UITimeout,
+		tools:              normalized.Tools,
+		approveToolCall:    normalized.ApproveToolCall,
+		runQueue:           normalized.RunQueue,
+		stderrLimit:        normalized.StderrLimit,
+		stderrWriter:       normalized.StderrWriter,
+		stderrLogger:       normalized.StderrLogger,
+		logger:             normalized.Logger,
 		useSession:         true,
 	})
 	if err != nil {
@@ -135,6 +163,15 @@ func StartOneShot(options OneShotOptions) (*OneShotClient, error) {
 		seedAuthFromHome:   normalized.SeedAuthFromHome,
 		skills:             normalized.Skills,
 		compactionPrompt:   normalized.CompactionPrompt,
+		uiHandler:          normalized.UIHandler,
+		uiTimeout:          normalized.UITimeout,
+		tools:              normalized.Tools,
+		approveToolCall:    normalized.ApproveToolCall,
+		runQueue:           normalized.RunQueue,
+		stderrLimit:        normalized.StderrLimit,
+		stderrWriter:       normalized.StderrWriter,
+		stderrLogger:       normalized.StderrLogger,
+		logger:             normalized.Logger,
 		useSession:         false,
 	})
 	if err != nil {
@@ -144,18 +181,15 @@ func StartOneShot(options OneShotOptions) (*OneShotClient, error) {
 }
 
 func startClient(config startConfig) (client *Client, err error) {
-	var hook *managedCompactionHook
-	if strings.TrimSpace(config.compactionPrompt) != "" {
-		hook, err = createManagedCompactionHook(config.compactionPrompt)
+	extensions, err := createManagedExtensions(config)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
 		if err != nil {
-			return nil, err
+			cleanupManagedExtensions(extensions)
 		}
-		defer func() {
-			if err != nil && hook != nil {
-				_ = hook.cleanup()
-			}
-		}()
-	}
+	}()
 
 	modelConfig, err := resolveModelConfig(config.mode, config.dragons)
 	if err != nil {
@@ -170,8 +204,8 @@ func startClient(config startConfig) (client *Client, err error) {
 	}
 
 	environment := cloneStringMap(config.environment)
-	if hook != nil {
-		hook.injectEnvironment(environment)
+	for _, extension := range extensions {
+		extension.injectEnvironment(environment)
 	}
 	env, err := buildEnv(config.appName, config.inheritEnvironment, config.seedAuthFromHome, config.auth, environment)
 	if err != nil {
@@ -184,8 +218,8 @@ func startClient(config startConfig) (client *Client, err error) {
 		"--model", modelConfig.model,
 		"--thinking", modelConfig.thinking,
 	}
-	if hook != nil {
-		args = append(args, hook.arguments()...)
+	for _, extension := range extensions {
+		args = append(args, extension.arguments()...)
 	}
 	if config.useSession {
 		if strings.TrimSpace(config.sessionName) != "" {
@@ -225,29 +259,60 @@ func startClient(config startConfig) (client *Client, err error) {
 	if err != nil {
 		return nil, err
 	}
-	stderr, err := cmd.StderrPipe()
-	if err != nil {
-		return nil, err
-	}
 
 	client = &Client{
-		process:               cmd,
-		stdin:                 stdin,
-		requests:              transport.NewRequestManager(ErrClientClosed),
-		events:                newEventHub(),
-		closed:                make(chan struct{}),
-		waitDone:              make(chan struct{}),
-		eventQueue:            transport.NewQueue[Event](),
-		eventDispatchEnd:      make(chan struct{}),
-		managedCompactionHook: hook,
+		process:          cmd,
+		stdin:            stdin,
+		requests:         transport.NewRequestManager(ErrClientClosed),
+		events:           newEventHub(),
+		stderr:           runtime.NewByteRing(config.stderrLimit),
+		stderrLimit:      config.stderrLimit,
+		stderrLines:      stream.NewHub[StderrLine](ErrClientClosed, nil, "", nil),
+		stderrWriter:     config.stderrWriter,
+		stderrLogger:     config.stderrLogger,
+		closed:           make(chan struct{}),
+		waitDone:         make(chan struct{}),
+		eventQueue:       transport.NewQueue[Event](),
+		eventDispatchEnd: make(chan struct{}),
+		auth:             config.auth,
+		uiHandler:        config.uiHandler,
+		uiTimeout:        config.uiTimeout,
+		uiNotices:        transport.NewQueue[ExtensionUIRequest](),
+		extensions:       extensions,
+		tools:            toolsByName(config.tools),
+		hostCalls:        newHostCalls(),
+		approveToolCall:  config.approveToolCall,
+		runLock:          runtime.NewFIFOLock(),
+		runQueue:         config.runQueue,
 	}
 
+	// A writer (not StderrPipe) makes Wait drain stderr before returning, so the
+	// tail is complete when the process is reported dead.
+	cmd.Stderr = stderrWriter{client: client}
+	cmd.WaitDelay = defaultShutdownTimeout
+	logger := resolveLogger(config.logger)
+	logger.Debug("starting pi process",
+		"executable", command.Executable,
+		"args", redactArgs(command.WithArgs(args)),
+		"dir", config.workDir,
+		"env", envNames(env),
+	)
 	if err = cmd.Start(); err != nil {
+		logger.Error("pi process start failed", "error", err)
 		return nil, err
 	}
+	client.startedAt = time.Now()
+	client.logger = logger.With("pid", cmd.Process.Pid)
+	client.logger.Info("pi process started", "executable", command.Executable)
+	client.events.OnDrop(func(mode stream.Mode, event Event) {
+		client.logger.Warn("subscription dropped event", "mode", fromStreamMode(mode), "event_type", event.Type)
+	})
+	client.stderrLines.OnDrop(func(mode stream.Mode, _ StderrLine) {
+		client.logger.Warn("stderr subscription dropped line", "mode", fromStreamMode(mode))
+	})
 
-	go client.captureStderr(stderr)
 	go client.dispatchEvents()
+	go client.deliverUINotices()
 	go client.readStdout(stdout)
 	go client.waitForProcess()
 
@@ -266,6 +331,7 @@ func startClient(config startConfig) (client *Client, err error) {
 func (client *Client) Close() error {
 	client.closeOnce.Do(func() {
 		close(client.closed)
+		client.logger.Info("closing pi client")
 		if client.stdin != nil {
 			_ = client.stdin.Close()
 		}
@@ -276,38 +342,122 @@ func (client *Client) Close() error {
 		select {
 		case <-client.waitDone:
 		case <-time.After(defaultShutdownTimeout):
+			client.logger.Warn("pi process ignored SIGTERM, killing", "timeout", defaultShutdownTimeout)
 			if client.process != nil && client.process.Process != nil {
 				_ = client.process.Process.Kill()
 			}
 			<-client.waitDone
 		}
+		client.logger.Info("pi process stopped", "exit_code", client.process.ProcessState.ExitCode(), "uptime", time.Since(client.startedAt))
 
 		client.closeAll(nil)
 		select {
 		case <-client.eventDispatchEnd:
 		case <-time.After(250 * time.Millisecond):
 		}
-		if client.managedCompactionHook != nil {
-			_ = client.managedCompactionHook.cleanup()
-			client.managedCompactionHook = nil
-		}
+		cleanupManagedExtensions(client.extensions)
+		client.extensions = nil
 	})
 	return nil
 }
 
+// Stderr returns the retained pi stderr (the last StderrLimit bytes).
 func (client *Client) Stderr() string {
-	client.stderrMu.Lock()
-	defer client.stderrMu.Unlock()
-	return client.stderr.String()
+	data, _ := client.stderr.Bytes()
+	return string(data)
+}
+
+// StderrLines returns up to the last n non-empty retained stderr lines (all of
+// them when n <= 0). A line cut by StderrLimit is left out.
+func (client *Client) StderrLines(n int) []string {
+	data, truncated := client.stderr.Bytes()
+	text := string(data)
+	if truncated {
+		if newline := strings.IndexByte(text, '\n'); newline >= 0 {
+			text = text[newline+1:]
+		} else {
+			text = ""
+		}
+	}
+
+	var lines []string
+	for line := range strings.Lines(text) {
+		if line = strings.TrimRight(line, "\r\n"); strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	if n > 0 && len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
+
+// SubscribeStderr delivers pi stderr split into lines; a line longer than
+// StderrLimit arrives in StderrLimit-byte pieces. Backpressure follows
+// policy (Block mode stalls pi's stderr); no drop markers are emitted. The
+// channel closes once the process has exited and its stderr is drained.
+func (client *Client) SubscribeStderr(policy SubscriptionPolicy) (<-chan StderrLine, func(), error) {
+	if err := validateSubscriptionPolicy(policy); err != nil {
+		return nil, nil, err
+	}
+	return client.stderrLines.Subscribe(toStreamPolicy(policy))
 }
 
 func (client *Client) appendStderr(chunk []byte) {
 	if len(chunk) == 0 {
 		return
 	}
+	receivedAt := time.Now()
+	client.stderr.Write(chunk)
+	if client.stderrWriter != nil {
+		_, _ = client.stderrWriter.Write(chunk)
+	}
+
 	client.stderrMu.Lock()
-	_, _ = client.stderr.Write(chunk)
+	client.stderrPartial = append(client.stderrPartial, chunk...)
+	var lines []string
+	for {
+		newline := bytes.IndexByte(client.stderrPartial, '\n')
+		switch {
+		case newline >= 0 && newline <= client.stderrLimit:
+			lines = append(lines, string(bytes.TrimRight(client.stderrPartial[:newline], "\r")))
+			client.stderrPartial = client.stderrPartial[newline+1:]
+			continue
+		case len(client.stderrPartial) >= client.stderrLimit:
+			// A line longer than StderrLimit is published in StderrLimit pieces
+			// instead of buffering it until its newline arrives.
+			lines = append(lines, string(client.stderrPartial[:client.stderrLimit]))
+			client.stderrPartial = client.stderrPartial[client.stderrLimit:]
+			continue
+		}
+		break
+	}
 	client.stderrMu.Unlock()
+
+	for _, line := range lines {
+		client.publishStderrLine(StderrLine{Text: line, ReceivedAt: receivedAt})
+	}
+}
+
+// finishStderr flushes an unterminated last line and closes SubscribeStderr
+// channels. Call after Wait, once the stderr copy has finished.
+func (client *Client) finishStderr() {
+	client.stderrMu.Lock()
+	partial := string(bytes.TrimRight(client.stderrPartial, "\r"))
+	client.stderrPartial = nil
+	client.stderrMu.Unlock()
+
+	if partial != "" {
+		client.publishStderrLine(StderrLine{Text: partial, ReceivedAt: time.Now()})
+	}
+	client.stderrLines.Close()
+}
+
+func (client *Client) publishStderrLine(line StderrLine) {
+	if client.stderrLogger != nil {
+		client.stderrLogger.Info("pi stderr", "line", line.Text)
+	}
+	client.stderrLines.Publish(line)
 }
 
 func (client *Client) stopEventDispatch() {
@@ -315,9 +465,18 @@ func (client *Client) stopEventDispatch() {
 		if client.eventQueue != nil {
 			client.eventQueue.Close()
 		}
+		if client.uiNotices != nil {
+			client.uiNotices.Close()
+		}
 	})
 }
 
+func (client *Client) noteSessionChange() {
+	if client.sessionChanged != nil {
+		client.sessionChanged()
+	}
+}
+
 func (client *Client) nextRequestID() string {
 	value := atomic.AddUint64(&client.requestCounter, 1)
 	return fmt.Sprintf("req-%d", value)
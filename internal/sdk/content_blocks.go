@@ -0,0 +1,137 @@
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	ContentBlockText     = "text"
+	ContentBlockThinking = "thinking"
+	ContentBlockImage    = "image"
+	ContentBlockToolCall = "toolCall"
+)
+
+// ContentBlock is the sealed union of decoded message content blocks.
+// Switch on the concrete type; UnknownBlock keeps block types this SDK does not model yet.
+type ContentBlock interface {
+	BlockType() string
+	isContentBlock()
+}
+
+type TextBlock struct {
+	Text string `json:"text"`
+}
+
+type ThinkingBlock struct {
+	Thinking string `json:"thinking"`
+}
+
+type ImageBlock struct {
+	Data     string `json:"data"`
+	MIMEType string `json:"mimeType"`
+}
+
+type ToolCallBlock struct {
+	ID        string          `json:"id"`
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+type UnknownBlock struct {
+	Type string
+	Raw  json.RawMessage
+}
+
+func (TextBlock) BlockType() string          { return ContentBlockText }
+func (ThinkingBlock) BlockType() string      { return ContentBlockThinking }
+func (ImageBlock) BlockType() string         { return ContentBlockImage }
+func (ToolCallBlock) BlockType() string      { return ContentBlockToolCall }
+func (block UnknownBlock) BlockType() string { return block.Type }
+
+func (TextBlock) isContentBlock()     {}
+func (ThinkingBlock) isContentBlock() {}
+func (ImageBlock) isContentBlock()    {}
+func (ToolCallBlock) isContentBlock() {}
+func (UnknownBlock) isContentBlock()  {}
+
+// Blocks decodes the message content. Plain string content becomes one TextBlock.
+func (message AgentMessage) Blocks() ([]ContentBlock, error) {
+	return DecodeContentBlocks(message.Content)
+}
+
+func DecodeContentBlocks(content json.RawMessage) ([]ContentBlock, error) {
+	return decodeContentBlocks(content, false)
+}
+
+// decodeContentBlocks skips blocks that fail to decode when lenient; malformed
+// content (neither a string nor an array) always fails.
+func decodeContentBlocks(content json.RawMessage, lenient bool) ([]ContentBlock, error) {
+	trimmed := strings.TrimSpace(string(content))
+	if trimmed == "" || trimmed == "null" {
+		return nil, nil
+	}
+	if strings.HasPrefix(trimmed, "\"") {
+		var text string
+		if err := json.Unmarshal(content, &text); err != nil {
+			return nil, err
+		}
+		return []ContentBlock{TextBlock{Text: text}}, nil
+	}
+
+	var rawBlocks []json.RawMessage
+	if err := json.Unmarshal(content, &rawBlocks); err != nil {
+		return nil, err
+	}
+	blocks := make([]ContentBlock, 0, len(rawBlocks))
+	for index, raw := range rawBlocks {
+		block, err := decodeContentBlock(raw)
+		if err != nil {
+			if lenient {
+				continue
+			}
+			return nil, fmt.Errorf("content block %d: %w", index, err)
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
+
+func decodeContentBlock(raw json.RawMessage) (ContentBlock, error) {
+	var envelope struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return nil, err
+	}
+	switch envelope.Type {
+	case ContentBlockText:
+		return decodeBlock[TextBlock](raw)
+	case ContentBlockThinking:
+		return decodeBlock[ThinkingBlock](raw)
+	case ContentBlockImage:
+		return decodeBlock[ImageBlock](raw)
+	case ContentBlockToolCall:
+		block, err := decodeBlock[ToolCallBlock](raw)
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(block.(ToolCallBlock).ID) == "" {
+			return nil, fmt.Errorf("%w: toolCall block missing id", ErrProtocolViolation)
+		}
+		return block, nil
+	case "":
+		return nil, fmt.Errorf("%w: content block missing type", ErrProtocolViolation)
+	default:
+		return UnknownBlock{Type: envelope.Type, Raw: raw}, nil
+	}
+}
+
+func decodeBlock[T ContentBlock](raw json.RawMessage) (ContentBlock, error) {
+	var block T
+	if err := json.Unmarshal(raw, &block); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
@@ -0,0 +1,321 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	toolBridgeFileEnv = "PI_GOLANG_TOOLS_FILE"
+	// toolBridgeUITitle marks editor requests the generated extension uses to call Go tools.
+	toolBridgeUITitle = "pi-golang:tool-call"
+	// toolBridgeCancelKey marks setStatus requests the generated extension sends
+	// when pi aborts a bridged tool call; the status text is the tool call ID.
+	toolBridgeCancelKey = "pi-golang:tool-cancel"
+)
+
+var defaultToolTimeout = 2 * time.Minute
+
+var toolNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+var defaultToolParameters = json.RawMessage(`{"type":"object","properties":{}}`)
+
+// ToolDefinition is a Go-implemented tool registered with pi through an SDK-generated extension.
+type ToolDefinition struct {
+	Name        string
+	Label       string
+	Description string
+	// Parameters is the JSON Schema for tool arguments (defaults to an empty object schema).
+	Parameters json.RawMessage
+	// Timeout bounds one Execute call (default 2m). ctx is also cancelled when
+	// pi aborts the tool call or the client closes.
+	Timeout time.Duration
+	Execute func(ctx context.Context, args json.RawMessage) (ToolResult, error)
+}
+
+// ToolResult is returned to the model as the tool output.
+type ToolResult struct {
+	Text    string
+	Details json.RawMessage
+}
+
+type toolBridgeDeclaration struct {
+	Name        string          `json:"name"`
+	Label       string          `json:"label"`
+	Description string          `json:"description"`
+	Parameters  json.RawMessage `json:"parameters"`
+}
+
+type toolBridgeCall struct {
+	ToolCallID string          `json:"toolCallId"`
+	Name       string          `json:"name"`
+	Params     json.RawMessage `json:"params"`
+}
+
+type toolBridgeReply struct {
+	Text    string          `json:"text,omitempty"`
+	Details json.RawMessage `json:"details,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
+
+type managedToolBridge struct {
+	bundleDir     string
+	extensionPath string
+	toolsPath     string
+}
+
+func normalizeToolDefinitions(tools []ToolDefinition) ([]ToolDefinition, error) {
+	if len(tools) == 0 {
+		return nil, nil
+	}
+	normalized := make([]ToolDefinition, 0, len(tools))
+	seen := make(map[string]struct{}, len(tools))
+	for _, tool := range tools {
+		tool.Name = strings.TrimSpace(tool.Name)
+		tool.Label = strings.TrimSpace(tool.Label)
+		tool.Description = strings.TrimSpace(tool.Description)
+		if !toolNamePattern.MatchString(tool.Name) {
+			return nil, fmt.Errorf("tool name %q must match %s", tool.Name, toolNamePattern)
+		}
+		if _, exists := seen[tool.Name]; exists {
+			return nil, fmt.Errorf("duplicate tool name %q", tool.Name)
+		}
+		if tool.Description == "" {
+			return nil, fmt.Errorf("tool %q description is required", tool.Name)
+		}
+		if tool.Execute == nil {
+			return nil, fmt.Errorf("tool %q execute func is required", tool.Name)
+		}
+		if tool.Timeout < 0 {
+			return nil, fmt.Errorf("tool %q timeout must be >= 0", tool.Name)
+		}
+		if tool.Label == "" {
+			tool.Label = tool.Name
+		}
+		if len(tool.Parameters) == 0 {
+			tool.Parameters = defaultToolParameters
+		}
+		var schema map[string]any
+		if err := json.Unmarshal(tool.Parameters, &schema); err != nil || schema == nil {
+			return nil, fmt.Errorf("tool %q parameters must be a JSON schema object", tool.Name)
+		}
+		seen[tool.Name] = struct{}{}
+		normalized = append(normalized, tool)
+	}
+	return normalized, nil
+}
+
+func createManagedToolBridge(tools []ToolDefinition) (*managedToolBridge, error) {
+	bundleDir, err := os.MkdirTemp("", "pi-golang-tools-")
+	if err != nil {
+		return nil, fmt.Errorf("create tools extension temp dir: %w", err)
+	}
+
+	declarations := make([]toolBridgeDeclaration, 0, len(tools))
+	for _, tool := range tools {
+		declarations = append(declarations, toolBridgeDeclaration{
+			Name:        tool.Name,
+			Label:       tool.Label,
+			Description: tool.Description,
+			Parameters:  tool.Parameters,
+		})
+	}
+	encoded, err := json.Marshal(declarations)
+	if err != nil {
+		_ = os.RemoveAll(bundleDir)
+		return nil, fmt.Errorf("encode tool definitions: %w", err)
+	}
+
+	toolsPath := filepath.Join(bundleDir, "tools.json")
+	if err := os.WriteFile(toolsPath, encoded, 0o600); err != nil {
+		_ = os.RemoveAll(bundleDir)
+		return nil, fmt.Errorf("write tool definitions file: %w", err)
+	}
+
+	extensionPath := filepath.Join(bundleDir, "go-tools.ts")
+	if err := os.WriteFile(extensionPath, []byte(renderToolBridgeExtension()), 0o600); err != nil {
+		_ = os.RemoveAll(bundleDir)
+		return nil, fmt.Errorf("write tools extension: %w", err)
+	}
+
+	return &managedToolBridge{
+		bundleDir:     bundleDir,
+		extensionPath: extensionPath,
+		toolsPath:     toolsPath,
+	}, nil
+}
+
+func (bridge *managedToolBridge) arguments() []string {
+	if bridge == nil {
+		return nil
+	}
+	return []string{"--extension", bridge.extensionPath}
+}
+
+func (bridge *managedToolBridge) injectEnvironment(values map[string]string) {
+	if bridge == nil {
+		return
+	}
+	values[toolBridgeFileEnv] = bridge.toolsPath
+}
+
+func (bridge *managedToolBridge) cleanup() error {
+	if bridge == nil || bridge.bundleDir == "" {
+		return nil
+	}
+	return os.RemoveAll(bridge.bundleDir)
+}
+
+func toolsByName(tools []ToolDefinition) map[string]ToolDefinition {
+	byName := make(map[string]ToolDefinition, len(tools))
+	for _, tool := range tools {
+		byName[tool.Name] = tool
+	}
+	return byName
+}
+
+func isToolBridgeRequest(request ExtensionUIRequest) bool {
+	return request.Method == UIMethodEditor && request.Title == toolBridgeUITitle
+}
+
+func isToolCancelRequest(request ExtensionUIRequest) bool {
+	return request.Method == UIMethodSetStatus && request.StatusKey == toolBridgeCancelKey
+}
+
+// startBridgedTool registers one Go tool call forwarded by the generated extension
+// and returns the work that answers it. Failures are encoded in the reply so the
+// extension can surface them as tool errors.
+func (client *Client) startBridgedTool(request ExtensionUIRequest) func() uiAnswer {
+	var call toolBridgeCall
+	if err := json.Unmarshal([]byte(request.Prefill), &call); err != nil {
+		return failedToolBridgeCall(fmt.Sprintf("decode tool call: %v", err))
+	}
+	tool, ok := client.tools[call.Name]
+	if !ok {
+		return failedToolBridgeCall(fmt.Sprintf("unknown tool %q", call.Name))
+	}
+
+	timeout := tool.Timeout
+	if timeout <= 0 {
+		timeout = defaultToolTimeout
+	}
+	ctx, cancel := client.untilClosedOrTimeout(timeout)
+	done := client.hostCalls.begin(request.ID, call.ToolCallID, cancel)
+	return func() uiAnswer {
+		defer done()
+		defer cancel()
+		return encodeToolBridgeReply(executeBridgedTool(ctx, tool, call.Params))
+	}
+}
+
+// executeBridgedTool stops waiting once ctx ends, even if Execute ignores it.
+func executeBridgedTool(ctx context.Context, tool ToolDefinition, params json.RawMessage) toolBridgeReply {
+	result := make(chan toolBridgeReply, 1)
+	go func() {
+		output, err := tool.Execute(ctx, params)
+		if err != nil {
+			result <- toolBridgeReply{Error: err.Error()}
+			return
+		}
+		result <- toolBridgeReply{Text: output.Text, Details: output.Details}
+	}()
+
+	select {
+	case reply := <-result:
+		return reply
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return toolBridgeReply{Error: fmt.Sprintf("tool %q timed out", tool.Name)}
+		}
+		return toolBridgeReply{Error: fmt.Sprintf("tool %q cancelled", tool.Name)}
+	}
+}
+
+func failedToolBridgeCall(message string) func() uiAnswer {
+	return func() uiAnswer { return encodeToolBridgeReply(toolBridgeReply{Error: message}) }
+}
+
+func encodeToolBridgeReply(reply toolBridgeReply) uiAnswer {
+	encoded, err := json.Marshal(reply)
+	if err != nil {
+		encoded, _ = json.Marshal(toolBridgeReply{Error: fmt.Sprintf("encode tool result: %v", err)})
+	}
+	return uiAnswer{value: string(encoded)}
+}
+
+func renderToolBridgeExtension() string {
+	return fmt.Sprintf(`import { readFileSync } from "node:fs";
+import type { ExtensionAPI } from "@mariozechner/pi-coding-agent";
+
+const TOOLS_FILE_ENV = %q;
+const TOOL_CALL_TITLE = %q;
+const TOOL_CANCEL_KEY = %q;
+
+type BridgedTool = {
+	name: string;
+	label: string;
+	description: string;
+	parameters: Record<string, unknown>;
+};
+
+type BridgedReply = {
+	text?: string;
+	details?: unknown;
+	error?: string;
+};
+
+function readTools(): BridgedTool[] {
+	const toolsPath = process.env[TOOLS_FILE_ENV];
+	if (!toolsPath) return [];
+	try {
+		return JSON.parse(readFileSync(toolsPath, "utf-8")) as BridgedTool[];
+	} catch {
+		return [];
+	}
+}
+
+export default function (pi: ExtensionAPI) {
+	for (const tool of readTools()) {
+		pi.registerTool({
+			name: tool.name,
+			label: tool.label,
+			description: tool.description,
+			parameters: tool.parameters as any,
+			async execute(toolCallId, params, signal, _onUpdate, ctx) {
+				if (signal?.aborted) {
+					throw new Error("tool " + tool.name + ": aborted");
+				}
+				const call = JSON.stringify({ toolCallId, name: tool.name, params });
+				// Tell the host to cancel its ctx; it still answers the editor request.
+				const onAbort = () => ctx.ui.setStatus(TOOL_CANCEL_KEY, toolCallId);
+				signal?.addEventListener("abort", onAbort, { once: true });
+				let raw: string | undefined;
+				try {
+					raw = await ctx.ui.editor(TOOL_CALL_TITLE, call);
+				} finally {
+					signal?.removeEventListener("abort", onAbort);
+				}
+				if (raw === undefined) {
+					throw new Error("tool " + tool.name + ": no result from host");
+				}
+				const reply = JSON.parse(raw) as BridgedReply;
+				if (reply.error) {
+					throw new Error(reply.error);
+				}
+				return {
+					content: [{ type: "text" as const, text: reply.text ?? "" }],
+					details: reply.details,
+				};
+			},
+		});
+	}
+}
+`, toolBridgeFileEnv, toolBridgeUITitle, toolBridgeCancelKey)
+}
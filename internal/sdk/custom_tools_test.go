@@ -0,0 +1,138 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/joshp123/pi-golang/internal/testsupport"
+)
+
+func testToolDefinition(name string) ToolDefinition {
+	return ToolDefinition{
+		Name:        name,
+		Description: "test tool",
+		Execute: func(context.Context, json.RawMessage) (ToolResult, error) {
+			return ToolResult{Text: "ok"}, nil
+		},
+	}
+}
+
+func TestNormalizeToolDefinitionsValidates(t *testing.T) {
+	tools, err := normalizeToolDefinitions([]ToolDefinition{testToolDefinition(" lookup_ticket ")})
+	if err != nil {
+		t.Fatalf("normalizeToolDefinitions returned error: %v", err)
+	}
+	if tools[0].Name != "lookup_ticket" || tools[0].Label != "lookup_ticket" {
+		t.Fatalf("unexpected normalized tool: %+v", tools[0])
+	}
+	if string(tools[0].Parameters) != string(defaultToolParameters) {
+		t.Fatalf("expected default parameters schema, got %s", tools[0].Parameters)
+	}
+
+	invalid := []ToolDefinition{
+		testToolDefinition("bad name"),
+		{Name: "no_description", Execute: testToolDefinition("x").Execute},
+		{Name: "no_execute", Description: "missing execute"},
+	}
+	for _, tool := range invalid {
+		if _, err := normalizeToolDefinitions([]ToolDefinition{tool}); err == nil {
+			t.Fatalf("expected validation error for %+v", tool)
+		}
+	}
+
+	badSchema := testToolDefinition("bad_schema")
+	badSchema.Parameters = json.RawMessage(`[1,2]`)
+	if _, err := normalizeToolDefinitions([]ToolDefinition{badSchema}); err == nil {
+		t.Fatal("expected non-object schema to be rejected")
+	}
+	if _, err := normalizeToolDefinitions([]ToolDefinition{testToolDefinition("dup"), testToolDefinition("dup")}); err == nil {
+		t.Fatal("expected duplicate tool names to be rejected")
+	}
+}
+
+func TestCreateManagedToolBridgeWritesBundle(t *testing.T) {
+	tool := testToolDefinition("lookup_ticket")
+	tool.Label = "Lookup ticket"
+	tool.Parameters = json.RawMessage(`{"type":"object","properties":{"id":{"type":"string"}},"required":["id"]}`)
+
+	bridge, err := createManagedToolBridge([]ToolDefinition{tool})
+	if err != nil {
+		t.Fatalf("createManagedToolBridge returned error: %v", err)
+	}
+	defer bridge.cleanup()
+
+	content, err := os.ReadFile(bridge.toolsPath)
+	if err != nil {
+		t.Fatalf("failed to read tools file: %v", err)
+	}
+	var declarations []toolBridgeDeclaration
+	if err := json.Unmarshal(content, &declarations); err != nil {
+		t.Fatalf("tools file is not valid JSON: %v", err)
+	}
+	if len(declarations) != 1 || declarations[0].Name != "lookup_ticket" || declarations[0].Label != "Lookup ticket" {
+		t.Fatalf("unexpected declarations: %+v", declarations)
+	}
+
+	source, err := os.ReadFile(bridge.extensionPath)
+	if err != nil {
+		t.Fatalf("failed to read extension file: %v", err)
+	}
+	for _, want := range []string{toolBridgeFileEnv, toolBridgeUITitle, toolBridgeCancelKey, "registerTool"} {
+		if !strings.Contains(string(source), want) {
+			t.Fatalf("expected extension source to contain %q", want)
+		}
+	}
+
+	env := map[string]string{}
+	bridge.injectEnvironment(env)
+	if env[toolBridgeFileEnv] != bridge.toolsPath {
+		t.Fatalf("unexpected tools env value: %q", env[toolBridgeFileEnv])
+	}
+}
+
+func TestStartClientShipsAllManagedExtensionsAndCleansUp(t *testing.T) {
+	testsupport.SetupFakePI(t, "never_respond")
+
+	client, err := startClient(startConfig{
+		appName:          "pi-golang-test",
+		mode:             ModeSmart,
+		auth:             ProviderAuth{Anthropic: AnthropicAuth{APIKey: Credential{Value: "test-key"}}},
+		compactionPrompt: "keep concise summary",
+		tools:            []ToolDefinition{testToolDefinition("lookup_ticket")},
+		approveToolCall: func(context.Context, ToolCallRequest) (Decision, error) {
+			return Decision{Action: DecisionAllow}, nil
+		},
+		useSession: false,
+	})
+	if err != nil {
+		t.Fatalf("startClient returned error: %v", err)
+	}
+
+	var extensionPaths []string
+	for index, arg := range client.process.Args {
+		if arg == "--extension" && index+1 < len(client.process.Args) {
+			extensionPaths = append(extensionPaths, client.process.Args[index+1])
+		}
+	}
+	if len(extensionPaths) != 3 {
+		_ = client.Close()
+		t.Fatalf("expected three --extension arguments, args=%v", client.process.Args)
+	}
+	if strings.TrimSpace(envSliceToMap(client.process.Env)[toolBridgeFileEnv]) == "" {
+		_ = client.Close()
+		t.Fatalf("expected %s env var", toolBridgeFileEnv)
+	}
+
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	for _, path := range extensionPaths {
+		if _, err := os.Stat(filepath.Dir(path)); !os.IsNotExist(err) {
+			t.Fatalf("expected bundle dir %s to be removed, stat err=%v", filepath.Dir(path), err)
+		}
+	}
+}
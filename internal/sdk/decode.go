@@ -7,16 +7,11 @@ import (
 	"strings"
 )
 
-func debugExtract(format string, args ...any) {
-	if debugEnabledProvider() {
-		debugf("extract: "+format, args...)
+func DecodeAgentStart(raw json.RawMessage) (AgentStartEvent, error) {
+	if err := decodeEmptyEvent(raw, EventTypeAgentStart); err != nil {
+		return AgentStartEvent{}, err
 	}
-}
-
-type contentBlock struct {
-	Type     string `json:"type"`
-	Text     string `json:"text,omitempty"`
-	Thinking string `json:"thinking,omitempty"`
+	return AgentStartEvent{}, nil
 }
 
 func DecodeAgentEnd(raw json.RawMessage) (AgentEndEvent, error) {
@@ -33,6 +28,68 @@ func DecodeAgentEnd(raw json.RawMessage) (AgentEndEvent, error) {
 	return AgentEndEvent{Messages: payload.Messages}, nil
 }
 
+func DecodeTurnStart(raw json.RawMessage) (TurnStartEvent, error) {
+	if err := decodeEmptyEvent(raw, EventTypeTurnStart); err != nil {
+		return TurnStartEvent{}, err
+	}
+	return TurnStartEvent{}, nil
+}
+
+func DecodeTurnEnd(raw json.RawMessage) (TurnEndEvent, error) {
+	var payload struct {
+		Type        string         `json:"type"`
+		Message     AgentMessage   `json:"message"`
+		ToolResults []AgentMessage `json:"toolResults"`
+	}
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return TurnEndEvent{}, err
+	}
+	if err := requireEnvelopeType("event", payload.Type, EventTypeTurnEnd); err != nil {
+		return TurnEndEvent{}, err
+	}
+	return TurnEndEvent{Message: payload.Message, ToolResults: payload.ToolResults}, nil
+}
+
+func DecodeMessageStart(raw json.RawMessage) (MessageStartEvent, error) {
+	message, err := decodeMessageEvent(raw, EventTypeMessageStart)
+	if err != nil {
+		return MessageStartEvent{}, err
+	}
+	return MessageStartEvent{Message: message}, nil
+}
+
+func DecodeMessageEnd(raw json.RawMessage) (MessageEndEvent, error) {
+	message, err := decodeMessageEvent(raw, EventTypeMessageEnd)
+	if err != nil {
+		return MessageEndEvent{}, err
+	}
+	return MessageEndEvent{Message: message}, nil
+}
+
+func decodeEmptyEvent(raw json.RawMessage, expected string) error {
+	var payload struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return err
+	}
+	return requireEnvelopeType("event", payload.Type, expected)
+}
+
+func decodeMessageEvent(raw json.RawMessage, expected string) (AgentMessage, error) {
+	var payload struct {
+		Type    string       `json:"type"`
+		Message AgentMessage `json:"message"`
+	}
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return AgentMessage{}, err
+	}
+	if err := requireEnvelopeType("event", payload.Type, expected); err != nil {
+		return AgentMessage{}, err
+	}
+	return payload.Message, nil
+}
+
 func DecodeMessageUpdate(raw json.RawMessage) (MessageUpdateEvent, error) {
 	var payload struct {
 		Type                  string          `json:"type"`
@@ -138,6 +195,114 @@ func DecodeAutoRetryEnd(raw json.RawMessage) (AutoRetryEndEvent, error) {
 	}, nil
 }
 
+func DecodeToolExecutionStart(raw json.RawMessage) (ToolExecutionStartEvent, error) {
+	var payload struct {
+		Type       string          `json:"type"`
+		ToolCallID string          `json:"toolCallId"`
+		ToolName   string          `json:"toolName"`
+		Args       json.RawMessage `json:"args"`
+	}
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return ToolExecutionStartEvent{}, err
+	}
+	if err := requireEnvelopeType("event", payload.Type, EventTypeToolExecutionStart); err != nil {
+		return ToolExecutionStartEvent{}, err
+	}
+	if err := requireToolCallID(payload.Type, payload.ToolCallID); err != nil {
+		return ToolExecutionStartEvent{}, err
+	}
+	return ToolExecutionStartEvent{
+		ToolCallID: payload.ToolCallID,
+		ToolName:   payload.ToolName,
+		Args:       payload.Args,
+	}, nil
+}
+
+func DecodeToolExecutionUpdate(raw json.RawMessage) (ToolExecutionUpdateEvent, error) {
+	var payload struct {
+		Type          string          `json:"type"`
+		ToolCallID    string          `json:"toolCallId"`
+		ToolName      string          `json:"toolName"`
+		Args          json.RawMessage `json:"args"`
+		PartialResult json.RawMessage `json:"partialResult"`
+	}
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return ToolExecutionUpdateEvent{}, err
+	}
+	if err := requireEnvelopeType("event", payload.Type, EventTypeToolExecutionUpdate); err != nil {
+		return ToolExecutionUpdateEvent{}, err
+	}
+	if err := requireToolCallID(payload.Type, payload.ToolCallID); err != nil {
+		return ToolExecutionUpdateEvent{}, err
+	}
+	return ToolExecutionUpdateEvent{
+		ToolCallID:    payload.ToolCallID,
+		ToolName:      payload.ToolName,
+		Args:          payload.Args,
+		PartialResult: payload.PartialResult,
+	}, nil
+}
+
+func DecodeToolExecutionEnd(raw json.RawMessage) (ToolExecutionEndEvent, error) {
+	var payload struct {
+		Type       string          `json:"type"`
+		ToolCallID string          `json:"toolCallId"`
+		ToolName   string          `json:"toolName"`
+		Result     json.RawMessage `json:"result"`
+		IsError    bool            `json:"isError"`
+	}
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return ToolExecutionEndEvent{}, err
+	}
+	if err := requireEnvelopeType("event", payload.Type, EventTypeToolExecutionEnd); err != nil {
+		return ToolExecutionEndEvent{}, err
+	}
+	if err := requireToolCallID(payload.Type, payload.ToolCallID); err != nil {
+		return ToolExecutionEndEvent{}, err
+	}
+	return ToolExecutionEndEvent{
+		ToolCallID: payload.ToolCallID,
+		ToolName:   payload.ToolName,
+		Result:     payload.Result,
+		IsError:    payload.IsError,
+	}, nil
+}
+
+func DecodeExtensionUIRequest(raw json.RawMessage) (ExtensionUIRequest, error) {
+	var payload struct {
+		Type string `json:"type"`
+		ExtensionUIRequest
+	}
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return ExtensionUIRequest{}, err
+	}
+	if err := requireEnvelopeType("event", payload.Type, EventTypeExtensionUIRequest); err != nil {
+		return ExtensionUIRequest{}, err
+	}
+	if strings.TrimSpace(payload.ID) == "" {
+		return ExtensionUIRequest{}, fmt.Errorf("%w: %s missing id", ErrProtocolViolation, payload.Type)
+	}
+	if strings.TrimSpace(string(payload.Method)) == "" {
+		return ExtensionUIRequest{}, fmt.Errorf("%w: %s missing method", ErrProtocolViolation, payload.Type)
+	}
+	return payload.ExtensionUIRequest, nil
+}
+
+func DecodeToolCallDenied(raw json.RawMessage) (ToolCallDeniedEvent, error) {
+	parsed, err := decodeSDKEvent[ToolCallDeniedEvent](raw, EventTypeToolCallDenied)
+	if err != nil {
+		return ToolCallDeniedEvent{}, err
+	}
+	return parsed, nil
+}
+
+func requireToolCallID(eventType string, toolCallID string) error {
+	if strings.TrimSpace(toolCallID) == "" {
+		return fmt.Errorf("%w: %s missing toolCallId", ErrProtocolViolation, eventType)
+	}
+	return nil
+}
+
 func requireEnvelopeType(kind string, actual string, expected string) error {
 	if strings.TrimSpace(actual) == "" {
 		return fmt.Errorf("%w: %s missing type", ErrProtocolViolation, kind)
@@ -160,13 +325,15 @@ func DecodeTerminalOutcome(raw json.RawMessage) (TerminalOutcome, error) {
 		if message.Role != "assistant" {
 			continue
 		}
-		text, err := extractAssistantText(message.Content)
+		content, err := extractAssistantContent(message)
 		if err != nil {
 			return TerminalOutcome{}, err
 		}
 		return TerminalOutcome{
 			Status:         terminalStatus(message.StopReason, message.ErrorMessage),
-			Text:           text,
+			Text:           content.text,
+			Thinking:       content.thinking,
+			ToolCalls:      content.toolCalls,
 			StopReason:     strings.TrimSpace(message.StopReason),
 			TerminalReason: terminalReasonFromMessage(message),
 			ErrorMessage:   strings.TrimSpace(message.ErrorMessage),
@@ -211,40 +378,40 @@ func terminalStatus(stopReason string, errorMessage string) TerminalStatus {
 }
 
 func extractRunResult(event Event) (RunResult, error) {
-	debugExtract("parsing agent_end payload (%d bytes)", len(event.Raw))
-
 	outcome, err := DecodeTerminalOutcome(event.Raw)
 	if err != nil {
-		debugExtract("decode error: %v", err)
 		return RunResult{}, err
 	}
-	debugExtract("terminal status=%s", outcome.Status)
 	return RunResult{Text: outcome.Text, Usage: outcome.Usage}, nil
 }
 
-func extractAssistantText(content json.RawMessage) (string, error) {
-	trimmed := strings.TrimSpace(string(content))
-	if trimmed == "" || trimmed == "null" {
-		return "", nil
-	}
-	if strings.HasPrefix(trimmed, "\"") {
-		var text string
-		if err := json.Unmarshal(content, &text); err != nil {
-			return "", err
-		}
-		return text, nil
-	}
+type assistantContent struct {
+	text      string
+	thinking  string
+	toolCalls []ToolCallBlock
+}
 
-	var blocks []contentBlock
-	if err := json.Unmarshal(content, &blocks); err != nil {
-		return "", err
+// extractAssistantContent is lenient: a malformed block is skipped so the rest
+// of the outcome survives. AgentMessage.Blocks stays strict.
+func extractAssistantContent(message AgentMessage) (assistantContent, error) {
+	blocks, err := decodeContentBlocks(message.Content, true)
+	if err != nil {
+		return assistantContent{}, err
 	}
 
-	var builder strings.Builder
+	var content assistantContent
+	var text, thinking strings.Builder
 	for _, block := range blocks {
-		if block.Type == "text" {
-			builder.WriteString(block.Text)
+		switch block := block.(type) {
+		case TextBlock:
+			text.WriteString(block.Text)
+		case ThinkingBlock:
+			thinking.WriteString(block.Thinking)
+		case ToolCallBlock:
+			content.toolCalls = append(content.toolCalls, block)
 		}
 	}
-	return builder.String(), nil
+	content.text = text.String()
+	content.thinking = thinking.String()
+	return content, nil
 }
@@ -0,0 +1,169 @@
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// TypedEvent is the sealed union returned by DecodeEvent.
+// Switch on the concrete type; UnknownEvent keeps event types this SDK does not model yet.
+type TypedEvent interface {
+	EventType() string
+	isTypedEvent()
+}
+
+// ProcessDiedEvent is the SDK-emitted process_died event. The exit details
+// mirror ProcessExitError; ExitCode is nil when the process was signaled or
+// its status is unknown.
+type ProcessDiedEvent struct {
+	Error             string   `json:"error,omitempty"`
+	ExitCode          *int     `json:"exitCode,omitempty"`
+	Signal            string   `json:"signal,omitempty"`
+	SDKInitiated      bool     `json:"sdkInitiated,omitempty"`
+	StderrTail        []string `json:"stderrTail,omitempty"`
+	UptimeMs          int64    `json:"uptimeMs,omitempty"`
+	InFlightRequestID string   `json:"inFlightRequestId,omitempty"`
+}
+
+// ProcessRestartedEvent is the Supervisor-emitted process_restarted event.
+// Error is the cause of the crash that triggered the restart.
+type ProcessRestartedEvent struct {
+	Restart     int    `json:"restart"`
+	SessionFile string `json:"sessionFile,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// SubscriptionDropEvent is the SDK-emitted subscription_drop event.
+type SubscriptionDropEvent struct {
+	Mode        SubscriptionMode `json:"mode"`
+	DroppedType string           `json:"droppedType"`
+}
+
+// UnknownEvent preserves events without a typed decoder (forward compatibility).
+type UnknownEvent struct {
+	Type string
+	Raw  json.RawMessage
+}
+
+// InvalidEvent is delivered by SubscribeTyped when a known event type fails to decode.
+type InvalidEvent struct {
+	Type string
+	Raw  json.RawMessage
+	Err  error
+}
+
+func (AgentStartEvent) EventType() string          { return EventTypeAgentStart }
+func (AgentEndEvent) EventType() string            { return EventTypeAgentEnd }
+func (TurnStartEvent) EventType() string           { return EventTypeTurnStart }
+func (TurnEndEvent) EventType() string             { return EventTypeTurnEnd }
+func (MessageStartEvent) EventType() string        { return EventTypeMessageStart }
+func (MessageUpdateEvent) EventType() string       { return EventTypeMessageUpdate }
+func (MessageEndEvent) EventType() string          { return EventTypeMessageEnd }
+func (ToolExecutionStartEvent) EventType() string  { return EventTypeToolExecutionStart }
+func (ToolExecutionUpdateEvent) EventType() string { return EventTypeToolExecutionUpdate }
+func (ToolExecutionEndEvent) EventType() string    { return EventTypeToolExecutionEnd }
+func (AutoCompactionStartEvent) EventType() string { return EventTypeAutoCompactionStart }
+func (AutoCompactionEndEvent) EventType() string   { return EventTypeAutoCompactionEnd }
+func (AutoRetryStartEvent) EventType() string      { return EventTypeAutoRetryStart }
+func (AutoRetryEndEvent) EventType() string        { return EventTypeAutoRetryEnd }
+func (ExtensionUIRequest) EventType() string       { return EventTypeExtensionUIRequest }
+func (ToolCallDeniedEvent) EventType() string      { return EventTypeToolCallDenied }
+func (ProcessDiedEvent) EventType() string         { return EventTypeProcessDied }
+func (ProcessRestartedEvent) EventType() string    { return EventTypeProcessRestarted }
+func (SubscriptionDropEvent) EventType() string    { return EventTypeSubscriptionDrop }
+func (event UnknownEvent) EventType() string       { return event.Type }
+func (event InvalidEvent) EventType() string       { return event.Type }
+
+func (AgentStartEvent) isTypedEvent()          {}
+func (AgentEndEvent) isTypedEvent()            {}
+func (TurnStartEvent) isTypedEvent()           {}
+func (TurnEndEvent) isTypedEvent()             {}
+func (MessageStartEvent) isTypedEvent()        {}
+func (MessageUpdateEvent) isTypedEvent()       {}
+func (MessageEndEvent) isTypedEvent()          {}
+func (ToolExecutionStartEvent) isTypedEvent()  {}
+func (ToolExecutionUpdateEvent) isTypedEvent() {}
+func (ToolExecutionEndEvent) isTypedEvent()    {}
+func (AutoCompactionStartEvent) isTypedEvent() {}
+func (AutoCompactionEndEvent) isTypedEvent()   {}
+func (AutoRetryStartEvent) isTypedEvent()      {}
+func (AutoRetryEndEvent) isTypedEvent()        {}
+func (ExtensionUIRequest) isTypedEvent()       {}
+func (ToolCallDeniedEvent) isTypedEvent()      {}
+func (ProcessDiedEvent) isTypedEvent()         {}
+func (ProcessRestartedEvent) isTypedEvent()    {}
+func (SubscriptionDropEvent) isTypedEvent()    {}
+func (UnknownEvent) isTypedEvent()             {}
+func (InvalidEvent) isTypedEvent()             {}
+
+// DecodeEvent decodes one event envelope into its typed variant.
+// Types without a decoder return UnknownEvent; decode failures of known types return an error.
+func DecodeEvent(event Event) (TypedEvent, error) {
+	switch event.Type {
+	case EventTypeAgentStart:
+		return typed(DecodeAgentStart(event.Raw))
+	case EventTypeAgentEnd:
+		return typed(DecodeAgentEnd(event.Raw))
+	case EventTypeTurnStart:
+		return typed(DecodeTurnStart(event.Raw))
+	case EventTypeTurnEnd:
+		return typed(DecodeTurnEnd(event.Raw))
+	case EventTypeMessageStart:
+		return typed(DecodeMessageStart(event.Raw))
+	case EventTypeMessageUpdate:
+		return typed(DecodeMessageUpdate(event.Raw))
+	case EventTypeMessageEnd:
+		return typed(DecodeMessageEnd(event.Raw))
+	case EventTypeToolExecutionStart:
+		return typed(DecodeToolExecutionStart(event.Raw))
+	case EventTypeToolExecutionUpdate:
+		return typed(DecodeToolExecutionUpdate(event.Raw))
+	case EventTypeToolExecutionEnd:
+		return typed(DecodeToolExecutionEnd(event.Raw))
+	case EventTypeAutoCompactionStart:
+		return typed(DecodeAutoCompactionStart(event.Raw))
+	case EventTypeAutoCompactionEnd:
+		return typed(DecodeAutoCompactionEnd(event.Raw))
+	case EventTypeAutoRetryStart:
+		return typed(DecodeAutoRetryStart(event.Raw))
+	case EventTypeAutoRetryEnd:
+		return typed(DecodeAutoRetryEnd(event.Raw))
+	case EventTypeExtensionUIRequest:
+		return typed(DecodeExtensionUIRequest(event.Raw))
+	case EventTypeToolCallDenied:
+		return typed(DecodeToolCallDenied(event.Raw))
+	case EventTypeProcessDied:
+		return typed(decodeSDKEvent[ProcessDiedEvent](event.Raw, EventTypeProcessDied))
+	case EventTypeProcessRestarted:
+		return typed(decodeSDKEvent[ProcessRestartedEvent](event.Raw, EventTypeProcessRestarted))
+	case EventTypeSubscriptionDrop:
+		return typed(decodeSDKEvent[SubscriptionDropEvent](event.Raw, EventTypeSubscriptionDrop))
+	default:
+		return UnknownEvent{Type: event.Type, Raw: event.Raw}, nil
+	}
+}
+
+func typed[T TypedEvent](event T, err error) (TypedEvent, error) {
+	if err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+func decodeSDKEvent[T any](raw json.RawMessage, expected string) (T, error) {
+	var zero T
+	var envelope struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return zero, err
+	}
+	if err := requireEnvelopeType("event", envelope.Type, expected); err != nil {
+		return zero, err
+	}
+	var payload T
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return zero, fmt.Errorf("decode %s: %w", expected, err)
+	}
+	return payload, nil
+}
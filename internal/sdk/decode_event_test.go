@@ -0,0 +1,142 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/joshp123/pi-golang/internal/testsupport"
+)
+
+func TestDecodeEventReturnsTypedVariants(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want TypedEvent
+	}{
+		{raw: `{"type":"agent_start"}`, want: AgentStartEvent{}},
+		{raw: `{"type":"turn_start"}`, want: TurnStartEvent{}},
+		{raw: `{"type":"auto_compaction_start","reason":"overflow"}`, want: AutoCompactionStartEvent{Reason: "overflow"}},
+		{raw: `{"type":"auto_retry_end","success":true,"attempt":2}`, want: AutoRetryEndEvent{Success: true, Attempt: 2}},
+	}
+
+	for _, test := range tests {
+		var envelope Event
+		if err := json.Unmarshal([]byte(test.raw), &envelope); err != nil {
+			t.Fatalf("unmarshal envelope: %v", err)
+		}
+		envelope.Raw = json.RawMessage(test.raw)
+
+		got, err := DecodeEvent(envelope)
+		if err != nil {
+			t.Fatalf("DecodeEvent(%s) returned error: %v", test.raw, err)
+		}
+		if got != test.want {
+			t.Fatalf("DecodeEvent(%s): got %#v want %#v", test.raw, got, test.want)
+		}
+		if got.EventType() != envelope.Type {
+			t.Fatalf("EventType mismatch: got %q want %q", got.EventType(), envelope.Type)
+		}
+	}
+}
+
+func TestDecodeEventSDKEvents(t *testing.T) {
+	died, err := DecodeEvent(newProcessDiedEvent(errors.New("exit status 1")))
+	if err != nil {
+		t.Fatalf("DecodeEvent(process_died) returned error: %v", err)
+	}
+	if parsed, ok := died.(ProcessDiedEvent); !ok || parsed.Error != "exit status 1" {
+		t.Fatalf("unexpected process_died variant: %#v", died)
+	}
+
+	drop, err := DecodeEvent(newSubscriptionDropEvent(SubscriptionModeRing, EventTypeMessageUpdate))
+	if err != nil {
+		t.Fatalf("DecodeEvent(subscription_drop) returned error: %v", err)
+	}
+	if parsed, ok := drop.(SubscriptionDropEvent); !ok || parsed.DroppedType != EventTypeMessageUpdate || parsed.Mode != SubscriptionModeRing {
+		t.Fatalf("unexpected subscription_drop variant: %#v", drop)
+	}
+}
+
+func TestDecodeEventToolCallDenied(t *testing.T) {
+	event := newToolCallDeniedEvent(ToolCallRequest{ToolCallID: "call-1", ToolName: "bash", Input: json.RawMessage(`{"command":"rm -rf build"}`)}, "destructive")
+	got, err := DecodeEvent(event)
+	if err != nil {
+		t.Fatalf("DecodeEvent(tool_call_denied) returned error: %v", err)
+	}
+	denied, ok := got.(ToolCallDeniedEvent)
+	if !ok {
+		t.Fatalf("expected ToolCallDeniedEvent, got %T", got)
+	}
+	if denied.ToolCallID != "call-1" || denied.ToolName != "bash" || denied.Reason != "destructive" || string(denied.Input) != `{"command":"rm -rf build"}` {
+		t.Fatalf("unexpected denied event: %+v", denied)
+	}
+}
+
+func TestDecodeEventUnknownPreservesRaw(t *testing.T) {
+	raw := json.RawMessage(`{"type":"future_event","x":1}`)
+	got, err := DecodeEvent(Event{Type: "future_event", Raw: raw})
+	if err != nil {
+		t.Fatalf("DecodeEvent returned error: %v", err)
+	}
+	unknown, ok := got.(UnknownEvent)
+	if !ok {
+		t.Fatalf("expected UnknownEvent, got %T", got)
+	}
+	if unknown.Type != "future_event" || string(unknown.Raw) != string(raw) {
+		t.Fatalf("unexpected unknown event: %+v", unknown)
+	}
+}
+
+func TestDecodeEventKnownTypeDecodeFailure(t *testing.T) {
+	_, err := DecodeEvent(Event{Type: EventTypeToolExecutionStart, Raw: json.RawMessage(`{"type":"tool_execution_start"}`)})
+	if !errors.Is(err, ErrProtocolViolation) {
+		t.Fatalf("expected protocol violation, got %v", err)
+	}
+}
+
+func TestSubscribeTypedDeliversDecodedEvents(t *testing.T) {
+	testsupport.SetupFakePI(t, "happy")
+
+	client, err := StartOneShot(testModelOneShotOptions())
+	if err != nil {
+		t.Fatalf("StartOneShot returned error: %v", err)
+	}
+	defer client.Close()
+
+	events, cancel, err := client.SubscribeTyped(DefaultSubscriptionPolicy())
+	if err != nil {
+		t.Fatalf("SubscribeTyped returned error: %v", err)
+	}
+	defer cancel()
+
+	if err := client.Prompt(context.Background(), PromptRequest{Message: "hello"}); err != nil {
+		t.Fatalf("Prompt returned error: %v", err)
+	}
+
+	var sawUpdate bool
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				t.Fatal("typed event channel closed")
+			}
+			switch event := event.(type) {
+			case MessageUpdateEvent:
+				sawUpdate = event.AssistantMessageEvent.Delta == "hello"
+			case AgentEndEvent:
+				if !sawUpdate {
+					t.Fatal("expected message_update before agent_end")
+				}
+				if len(event.Messages) != 2 {
+					t.Fatalf("unexpected agent_end messages: %+v", event.Messages)
+				}
+				return
+			}
+		case <-timeout:
+			t.Fatal("timeout waiting for typed agent_end")
+		}
+	}
+}
@@ -16,12 +16,26 @@ func TestExtractRunResult(t *testing.T) {
 	}
 }
 
-func TestExtractAssistantTextString(t *testing.T) {
-	result, err := extractAssistantText([]byte(`"plain"`))
+func TestExtractAssistantContentString(t *testing.T) {
+	result, err := extractAssistantContent(AgentMessage{Role: "assistant", Content: []byte(`"plain"`)})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if result != "plain" {
-		t.Fatalf("expected plain, got %q", result)
+	if result.text != "plain" || result.thinking != "" || result.toolCalls != nil {
+		t.Fatalf("expected plain text only, got %+v", result)
+	}
+}
+
+func TestExtractAssistantContentSkipsMalformedBlocks(t *testing.T) {
+	message := AgentMessage{Role: "assistant", Content: []byte(`[{"text":"no type"},{"type":"toolCall","name":"bash"},{"type":"text","text":"kept"}]`)}
+	result, err := extractAssistantContent(message)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.text != "kept" || result.toolCalls != nil {
+		t.Fatalf("expected malformed blocks to be skipped, got %+v", result)
+	}
+	if _, err := message.Blocks(); err == nil {
+		t.Fatal("expected Blocks to stay strict")
 	}
 }
@@ -2,6 +2,7 @@ package sdk
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/joshp123/pi-golang/internal/rpc"
@@ -182,3 +183,119 @@ func TestDecodeAutoRetryEnd(t *testing.T) {
 		t.Fatalf("unexpected auto_retry_end payload: %+v", event)
 	}
 }
+
+func TestDecodeToolExecutionEvents(t *testing.T) {
+	start, err := DecodeToolExecutionStart(json.RawMessage(`{"type":"tool_execution_start","toolCallId":"call-1","toolName":"bash","args":{"command":"ls"}}`))
+	if err != nil {
+		t.Fatalf("DecodeToolExecutionStart returned error: %v", err)
+	}
+	if start.ToolCallID != "call-1" || start.ToolName != "bash" || string(start.Args) != `{"command":"ls"}` {
+		t.Fatalf("unexpected tool_execution_start payload: %+v", start)
+	}
+
+	update, err := DecodeToolExecutionUpdate(json.RawMessage(`{"type":"tool_execution_update","toolCallId":"call-1","toolName":"bash","partialResult":{"content":[{"type":"text","text":"a"}]}}`))
+	if err != nil {
+		t.Fatalf("DecodeToolExecutionUpdate returned error: %v", err)
+	}
+	if len(update.PartialResult) == 0 {
+		t.Fatalf("expected partial result, got %+v", update)
+	}
+
+	end, err := DecodeToolExecutionEnd(json.RawMessage(`{"type":"tool_execution_end","toolCallId":"call-1","toolName":"bash","result":{"content":[]},"isError":true}`))
+	if err != nil {
+		t.Fatalf("DecodeToolExecutionEnd returned error: %v", err)
+	}
+	if !end.IsError || len(end.Result) == 0 {
+		t.Fatalf("unexpected tool_execution_end payload: %+v", end)
+	}
+}
+
+func TestDecodeToolExecutionRequiresToolCallID(t *testing.T) {
+	_, err := DecodeToolExecutionStart(json.RawMessage(`{"type":"tool_execution_start","toolName":"bash"}`))
+	if !errors.Is(err, ErrProtocolViolation) {
+		t.Fatalf("expected protocol violation, got %v", err)
+	}
+}
+
+func TestDecodeLifecycleEvents(t *testing.T) {
+	if _, err := DecodeAgentStart(json.RawMessage(`{"type":"agent_start"}`)); err != nil {
+		t.Fatalf("DecodeAgentStart returned error: %v", err)
+	}
+	if _, err := DecodeTurnStart(json.RawMessage(`{"type":"turn_start"}`)); err != nil {
+		t.Fatalf("DecodeTurnStart returned error: %v", err)
+	}
+
+	turnEnd, err := DecodeTurnEnd(json.RawMessage(`{
+		"type":"turn_end",
+		"message":{"role":"assistant","content":[],"stopReason":"toolUse"},
+		"toolResults":[{"role":"toolResult","toolCallId":"call-1","toolName":"bash","content":[{"type":"text","text":"ok"}],"isError":false}]
+	}`))
+	if err != nil {
+		t.Fatalf("DecodeTurnEnd returned error: %v", err)
+	}
+	if turnEnd.Message.StopReason != "toolUse" || len(turnEnd.ToolResults) != 1 || turnEnd.ToolResults[0].ToolCallID != "call-1" {
+		t.Fatalf("unexpected turn_end payload: %+v", turnEnd)
+	}
+
+	start, err := DecodeMessageStart(json.RawMessage(`{"type":"message_start","message":{"role":"user","content":"hi"}}`))
+	if err != nil {
+		t.Fatalf("DecodeMessageStart returned error: %v", err)
+	}
+	if start.Message.Role != "user" {
+		t.Fatalf("unexpected message_start role: %q", start.Message.Role)
+	}
+
+	end, err := DecodeMessageEnd(json.RawMessage(`{"type":"message_end","message":{"role":"assistant","content":[],"usage":{"input":1,"output":2}}}`))
+	if err != nil {
+		t.Fatalf("DecodeMessageEnd returned error: %v", err)
+	}
+	if end.Message.Usage == nil || end.Message.Usage.Output != 2 {
+		t.Fatalf("unexpected message_end usage: %+v", end.Message.Usage)
+	}
+}
+
+func TestDecodeLifecycleEventsRejectMismatchedType(t *testing.T) {
+	if _, err := DecodeTurnStart(json.RawMessage(`{"type":"turn_end"}`)); err == nil {
+		t.Fatal("expected type mismatch error")
+	}
+	if _, err := DecodeMessageEnd(json.RawMessage(`{"message":{"role":"assistant"}}`)); !errors.Is(err, ErrProtocolViolation) {
+		t.Fatalf("expected missing type protocol violation, got %v", err)
+	}
+}
+
+func TestDecodeExtensionUIRequestRequiresIDAndMethod(t *testing.T) {
+	request, err := DecodeExtensionUIRequest(json.RawMessage(`{"type":"extension_ui_request","id":"ui-1","method":"select","title":"Pick","options":["a","b"],"timeout":5000}`))
+	if err != nil {
+		t.Fatalf("DecodeExtensionUIRequest returned error: %v", err)
+	}
+	if request.Method != UIMethodSelect || len(request.Options) != 2 || request.TimeoutMS != 5000 {
+		t.Fatalf("unexpected request: %+v", request)
+	}
+
+	if _, err := DecodeExtensionUIRequest(json.RawMessage(`{"type":"extension_ui_request","method":"select"}`)); !errors.Is(err, ErrProtocolViolation) {
+		t.Fatalf("expected protocol violation for missing id, got %v", err)
+	}
+	if _, err := DecodeExtensionUIRequest(json.RawMessage(`{"type":"extension_ui_request","id":"ui-1"}`)); !errors.Is(err, ErrProtocolViolation) {
+		t.Fatalf("expected protocol violation for missing method, got %v", err)
+	}
+}
+
+func TestExtensionUIResponseCommandShapes(t *testing.T) {
+	selectResponse := extensionUIResponseCommand(ExtensionUIRequest{ID: "ui-1", Method: UIMethodSelect}, uiAnswer{value: "a"})
+	if selectResponse["type"] != rpc.CommandExtensionUIResponse || selectResponse["id"] != "ui-1" || selectResponse["value"] != "a" {
+		t.Fatalf("unexpected select response: %+v", selectResponse)
+	}
+
+	confirmResponse := extensionUIResponseCommand(ExtensionUIRequest{ID: "ui-2", Method: UIMethodConfirm}, defaultUIAnswer(UIMethodConfirm))
+	if confirmResponse["confirmed"] != false {
+		t.Fatalf("unexpected confirm default: %+v", confirmResponse)
+	}
+
+	cancelled := extensionUIResponseCommand(ExtensionUIRequest{ID: "ui-3", Method: UIMethodEditor}, defaultUIAnswer(UIMethodEditor))
+	if cancelled["cancelled"] != true {
+		t.Fatalf("unexpected editor default: %+v", cancelled)
+	}
+	if _, hasValue := cancelled["value"]; hasValue {
+		t.Fatalf("cancelled response must not carry a value: %+v", cancelled)
+	}
+}
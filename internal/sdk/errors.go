@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 )
 
 var (
@@ -19,6 +20,14 @@ var (
 	ErrRunInProgress = errors.New("run already in progress")
 	// ErrInvalidSubscriptionPolicy indicates an unsupported subscription mode or buffer.
 	ErrInvalidSubscriptionPolicy = errors.New("invalid subscription policy")
+	// ErrInvalidStructuredOutput indicates RunJSON exhausted repairs without a valid JSON value.
+	ErrInvalidStructuredOutput = errors.New("invalid structured output")
+	// ErrBudgetExceeded indicates a run was aborted because it tripped a Budget limit.
+	ErrBudgetExceeded = errors.New("run budget exceeded")
+	// ErrRunStalled indicates a run emitted no events for its IdleTimeout.
+	ErrRunStalled = errors.New("run stalled")
+	// ErrCrashLoop indicates a Supervisor gave up after too many restarts in its window.
+	ErrCrashLoop = errors.New("pi process crash loop")
 )
 
 // RPCError is returned when pi responds with success=false for a command.
@@ -44,3 +53,119 @@ func (err *RPCError) Error() string {
 	}
 	return fmt.Sprintf("rpc %s (%s) failed: %s", err.Command, err.RequestID, message)
 }
+
+// BudgetExceededError is returned by RunDetailedWithOptions when a Budget limit
+// trips. Partial holds everything observed up to (and including) the abort.
+type BudgetExceededError struct {
+	Limit   BudgetLimit
+	Budget  Budget
+	Spent   BudgetUsage
+	Partial RunDetailedResult
+}
+
+func (err *BudgetExceededError) Error() string {
+	if err == nil {
+		return ""
+	}
+	var spent, limit any
+	switch err.Limit {
+	case BudgetLimitInputTokens:
+		spent, limit = err.Spent.InputTokens, err.Budget.MaxInputTokens
+	case BudgetLimitOutputTokens:
+		spent, limit = err.Spent.OutputTokens, err.Budget.MaxOutputTokens
+	case BudgetLimitCost:
+		spent, limit = fmt.Sprintf("$%.4f", err.Spent.CostUSD), fmt.Sprintf("$%.4f", err.Budget.MaxCostUSD)
+	case BudgetLimitTurns:
+		spent, limit = err.Spent.Turns, err.Budget.MaxTurns
+	case BudgetLimitToolCalls:
+		spent, limit = err.Spent.ToolCalls, err.Budget.MaxToolCalls
+	case BudgetLimitWallTime:
+		spent, limit = err.Spent.WallTime.Round(time.Millisecond), err.Budget.MaxWallTime
+	default:
+		return ErrBudgetExceeded.Error()
+	}
+	return fmt.Sprintf("%s: %s %v > %v", ErrBudgetExceeded, err.Limit, spent, limit)
+}
+
+func (err *BudgetExceededError) Unwrap() error {
+	return ErrBudgetExceeded
+}
+
+// RunStalledError is returned by RunDetailedWithOptions when IdleTimeout
+// expires. Killed reports that abort was ignored and the process was killed
+// (the client then fails with ErrProcessDied).
+type RunStalledError struct {
+	IdleTimeout time.Duration
+	// LastEventType is the last event seen before the stall ("" if none).
+	LastEventType string
+	Killed        bool
+	Partial       RunDetailedResult
+}
+
+func (err *RunStalledError) Error() string {
+	if err == nil {
+		return ""
+	}
+	lastEvent := err.LastEventType
+	if lastEvent == "" {
+		lastEvent = "none"
+	}
+	message := fmt.Sprintf("%s: no events for %s (last event: %s)", ErrRunStalled, err.IdleTimeout, lastEvent)
+	if err.Killed {
+		message += "; abort ignored, process killed"
+	}
+	return message
+}
+
+func (err *RunStalledError) Unwrap() error {
+	return ErrRunStalled
+}
+
+// ProcessExitError describes why the pi process died. It satisfies
+// errors.Is(err, ErrProcessDied); Err is the underlying wait/read error, if any.
+type ProcessExitError struct {
+	// ExitCode is -1 when the process was signaled or its status is unknown.
+	ExitCode int
+	// Signal names the terminating signal ("" if it exited normally).
+	Signal string
+	// SDKInitiated reports that the SDK killed the process (e.g. a stalled run).
+	SDKInitiated bool
+	// StderrTail holds the last lines pi wrote to stderr.
+	StderrTail []string
+	Uptime     time.Duration
+	// InFlightRequestID is the latest request still awaiting a response ("" if none).
+	InFlightRequestID string
+	Err               error
+}
+
+func (err *ProcessExitError) Error() string {
+	if err == nil {
+		return ""
+	}
+	var detail string
+	switch {
+	case err.Signal != "":
+		detail = "signal " + err.Signal
+	case err.ExitCode >= 0:
+		detail = fmt.Sprintf("exit code %d", err.ExitCode)
+	case err.Err != nil:
+		detail = err.Err.Error()
+	default:
+		return ErrProcessDied.Error()
+	}
+	if err.SDKInitiated {
+		detail += ", killed by sdk"
+	}
+	message := fmt.Sprintf("%s: %s after %s", ErrProcessDied, detail, err.Uptime.Round(time.Millisecond))
+	if len(err.StderrTail) > 0 {
+		message += "; stderr: " + err.StderrTail[len(err.StderrTail)-1]
+	}
+	return message
+}
+
+func (err *ProcessExitError) Unwrap() []error {
+	if err.Err == nil {
+		return []error{ErrProcessDied}
+	}
+	return []error{ErrProcessDied, err.Err}
+}
@@ -0,0 +1,202 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+var defaultUITimeout = 2 * time.Minute
+
+// UIHandler answers upstream extension UI requests (extension_ui_request).
+//
+// Dialog methods run on their own goroutine and must honor ctx. Notify and
+// SetStatus are called one at a time, in the order pi sent them, so a slow one
+// delays the next. Returning an error,
+// or exceeding the timeout, sends the default answer: cancelled for select/input/editor
+// and confirmed=false for confirm. Without a UIHandler every dialog gets the default
+// answer immediately, so prompting extensions never hang.
+type UIHandler interface {
+	Select(ctx context.Context, request ExtensionUIRequest) (string, error)
+	Confirm(ctx context.Context, request ExtensionUIRequest) (bool, error)
+	Input(ctx context.Context, request ExtensionUIRequest) (string, error)
+	Editor(ctx context.Context, request ExtensionUIRequest) (string, error)
+	Notify(ctx context.Context, request ExtensionUIRequest)
+	SetStatus(ctx context.Context, request ExtensionUIRequest)
+}
+
+type uiAnswer struct {
+	value     string
+	confirmed bool
+	cancelled bool
+}
+
+func defaultUIAnswer(method UIMethod) uiAnswer {
+	if method == UIMethodConfirm {
+		return uiAnswer{confirmed: false}
+	}
+	return uiAnswer{cancelled: true}
+}
+
+func isUIDialog(method UIMethod) bool {
+	switch method {
+	case UIMethodSelect, UIMethodConfirm, UIMethodInput, UIMethodEditor:
+		return true
+	default:
+		return false
+	}
+}
+
+// handleExtensionUIRequest runs on the stdout reader so bridged tool calls are
+// registered before a later cancel marker or Abort; answers are produced on their own
+// goroutine and notices on the uiNotices worker. SDK plumbing (tool bridge,
+// approval gate) is not published.
+func (client *Client) handleExtensionUIRequest(event Event) {
+	request, err := DecodeExtensionUIRequest(event.Raw)
+	if err != nil {
+		client.logger.Warn("drop extension ui request", "error", err)
+		client.enqueueEvent(event)
+		return
+	}
+	if !isSDKUIRequest(request) {
+		client.enqueueEvent(event)
+	}
+
+	switch {
+	case isToolCancelRequest(request):
+		client.hostCalls.cancelToolCall(request.StatusText)
+	case isToolBridgeRequest(request):
+		go client.respondUI(request, client.startBridgedTool(request))
+	case client.approveToolCall != nil && isApprovalRequest(request):
+		go client.respondUI(request, client.startToolCallDecision(request))
+	case !isUIDialog(request.Method):
+		if client.uiHandler != nil {
+			_ = client.uiNotices.Push(request)
+		}
+	default:
+		go client.respondUI(request, func() uiAnswer { return client.resolveUIDialog(request) })
+	}
+}
+
+func isSDKUIRequest(request ExtensionUIRequest) bool {
+	return isToolBridgeRequest(request) || isToolCancelRequest(request) || isApprovalRequest(request)
+}
+
+func (client *Client) respondUI(request ExtensionUIRequest, answer func() uiAnswer) {
+	payload, err := json.Marshal(extensionUIResponseCommand(request, answer()))
+	if err != nil {
+		client.logger.Warn("encode extension ui response", "ui_request_id", request.ID, "error", err)
+		return
+	}
+	if err := client.writeFrame(payload); err != nil {
+		client.logger.Warn("write extension ui response", "ui_request_id", request.ID, "error", err)
+	}
+}
+
+// deliverUINotices is the single worker behind uiNotices, so a stale status
+// never overwrites a newer one.
+func (client *Client) deliverUINotices() {
+	for {
+		request, ok := client.uiNotices.Pop()
+		if !ok {
+			return
+		}
+		client.notifyUIHandler(request)
+	}
+}
+
+func (client *Client) notifyUIHandler(request ExtensionUIRequest) {
+	if client.uiHandler == nil {
+		return
+	}
+	ctx, cancel := client.uiContext(request)
+	defer cancel()
+
+	switch request.Method {
+	case UIMethodNotify:
+		client.uiHandler.Notify(ctx, request)
+	case UIMethodSetStatus:
+		client.uiHandler.SetStatus(ctx, request)
+	}
+}
+
+func (client *Client) resolveUIDialog(request ExtensionUIRequest) uiAnswer {
+	fallback := defaultUIAnswer(request.Method)
+	if client.uiHandler == nil {
+		return fallback
+	}
+
+	ctx, cancel := client.uiContext(request)
+	defer cancel()
+
+	result := make(chan uiAnswer, 1)
+	go func() {
+		answer, err := callUIDialog(ctx, client.uiHandler, request)
+		if err != nil {
+			client.logger.Warn("extension ui handler failed", "method", request.Method, "ui_request_id", request.ID, "error", err)
+			answer = fallback
+		}
+		result <- answer
+	}()
+
+	select {
+	case answer := <-result:
+		return answer
+	case <-ctx.Done():
+		return fallback
+	}
+}
+
+func callUIDialog(ctx context.Context, handler UIHandler, request ExtensionUIRequest) (uiAnswer, error) {
+	switch request.Method {
+	case UIMethodSelect:
+		value, err := handler.Select(ctx, request)
+		return uiAnswer{value: value}, err
+	case UIMethodConfirm:
+		confirmed, err := handler.Confirm(ctx, request)
+		return uiAnswer{confirmed: confirmed}, err
+	case UIMethodInput:
+		value, err := handler.Input(ctx, request)
+		return uiAnswer{value: value}, err
+	default:
+		value, err := handler.Editor(ctx, request)
+		return uiAnswer{value: value}, err
+	}
+}
+
+// uiContext bounds one UI request by the shorter of the client UITimeout and the
+// upstream request timeout, and cancels it when the client closes.
+func (client *Client) uiContext(request ExtensionUIRequest) (context.Context, context.CancelFunc) {
+	timeout := client.uiTimeout
+	if timeout <= 0 {
+		timeout = defaultUITimeout
+	}
+	if upstream := time.Duration(request.TimeoutMS) * time.Millisecond; upstream > 0 && upstream < timeout {
+		timeout = upstream
+	}
+	return client.untilClosedOrTimeout(timeout)
+}
+
+// untilClosedOrTimeout returns a context cancelled after timeout or when the
+// client closes.
+func (client *Client) untilClosedOrTimeout(timeout time.Duration) (context.Context, context.CancelFunc) {
+	parent, cancelParent := client.untilClosed()
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	return ctx, func() {
+		cancel()
+		cancelParent()
+	}
+}
+
+// untilClosed returns a context cancelled when the client closes.
+func (client *Client) untilClosed() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		select {
+		case <-client.closed:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
@@ -0,0 +1,66 @@
+package sdk
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// hostCalls tracks work the SDK does on pi's behalf (bridged tool calls and
+// approval decisions), while pi itself waits silently for the answer.
+type hostCalls struct {
+	mu       sync.Mutex
+	calls    map[string]hostCall
+	lastDone time.Time
+}
+
+type hostCall struct {
+	toolCallID string
+	cancel     context.CancelFunc
+}
+
+func newHostCalls() *hostCalls {
+	return &hostCalls{calls: map[string]hostCall{}}
+}
+
+// begin registers the call answering UI request requestID and returns its
+// completion func.
+func (calls *hostCalls) begin(requestID string, toolCallID string, cancel context.CancelFunc) func() {
+	calls.mu.Lock()
+	calls.calls[requestID] = hostCall{toolCallID: toolCallID, cancel: cancel}
+	calls.mu.Unlock()
+	return func() {
+		calls.mu.Lock()
+		delete(calls.calls, requestID)
+		calls.lastDone = time.Now()
+		calls.mu.Unlock()
+	}
+}
+
+// quietSince reports when the last call finished, or false while one is
+// outstanding (pi emits no events while it waits, so that is not idleness).
+func (calls *hostCalls) quietSince() (time.Time, bool) {
+	calls.mu.Lock()
+	defer calls.mu.Unlock()
+	return calls.lastDone, len(calls.calls) == 0
+}
+
+// cancelToolCall cancels every outstanding call for toolCallID.
+func (calls *hostCalls) cancelToolCall(toolCallID string) {
+	calls.mu.Lock()
+	defer calls.mu.Unlock()
+	for _, call := range calls.calls {
+		if call.toolCallID == toolCallID {
+			call.cancel()
+		}
+	}
+}
+
+// cancelAll cancels every outstanding call.
+func (calls *hostCalls) cancelAll() {
+	calls.mu.Lock()
+	defer calls.mu.Unlock()
+	for _, call := range calls.calls {
+		call.cancel()
+	}
+}
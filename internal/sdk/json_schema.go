@@ -0,0 +1,303 @@
+package sdk
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	timeType            = reflect.TypeFor[time.Time]()
+	rawMessageType      = reflect.TypeFor[json.RawMessage]()
+	jsonMarshalerType   = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+)
+
+// jsonSchema is the subset of JSON Schema derived from Go types for structured output.
+// A nil schema (or empty Type) accepts any value.
+type jsonSchema struct {
+	Type     string
+	Format   string
+	Nullable bool
+	// Unsigned rejects negative numbers (Go unsigned integers).
+	Unsigned   bool
+	Properties map[string]*jsonSchema
+	Required   []string
+	// Closed rejects object keys outside Properties (Go structs).
+	Closed bool
+	// Values is the schema of every value of a map-backed object.
+	Values *jsonSchema
+	Items  *jsonSchema
+}
+
+// schemaFor derives a schema from t following encoding/json field rules.
+// Types with custom JSON marshaling and recursive references are left unconstrained.
+func schemaFor(t reflect.Type) *jsonSchema {
+	return schemaForType(t, map[reflect.Type]bool{})
+}
+
+func schemaForType(t reflect.Type, visiting map[reflect.Type]bool) *jsonSchema {
+	if t.Kind() == reflect.Pointer {
+		schema := schemaForType(t.Elem(), visiting)
+		if schema.Type != "" {
+			schema.Nullable = true
+		}
+		return schema
+	}
+	switch {
+	case t == timeType:
+		return &jsonSchema{Type: "string", Format: "date-time"}
+	case t == rawMessageType:
+		return &jsonSchema{}
+	case t.Implements(jsonMarshalerType), reflect.PointerTo(t).Implements(jsonUnmarshalerType):
+		return &jsonSchema{}
+	case t.Implements(textMarshalerType):
+		return &jsonSchema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &jsonSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &jsonSchema{Type: "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &jsonSchema{Type: "integer", Unsigned: true}
+	case reflect.Float32, reflect.Float64:
+		return &jsonSchema{Type: "number"}
+	case reflect.String:
+		return &jsonSchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		// encoding/json base64-encodes []byte only; [N]byte stays a number array.
+		// Nil slices marshal as null, so slices (unlike arrays) are nullable.
+		nullable := t.Kind() == reflect.Slice
+		if nullable && t.Elem().Kind() == reflect.Uint8 {
+			return &jsonSchema{Type: "string", Format: "byte", Nullable: true}
+		}
+		return &jsonSchema{Type: "array", Nullable: nullable, Items: schemaForType(t.Elem(), visiting)}
+	case reflect.Map:
+		// Nil maps marshal as null.
+		if t.Key().Kind() != reflect.String {
+			return &jsonSchema{Type: "object", Nullable: true}
+		}
+		return &jsonSchema{Type: "object", Nullable: true, Values: schemaForType(t.Elem(), visiting)}
+	case reflect.Struct:
+		if visiting[t] {
+			return &jsonSchema{}
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+		schema := &jsonSchema{Type: "object", Properties: map[string]*jsonSchema{}, Closed: true}
+		addStructFields(schema, t, visiting)
+		sort.Strings(schema.Required)
+		return schema
+	default:
+		return &jsonSchema{}
+	}
+}
+
+func addStructFields(schema *jsonSchema, t reflect.Type, visiting map[reflect.Type]bool) {
+	for index := range t.NumField() {
+		field := t.Field(index)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				addStructFields(schema, embedded, visiting)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if hasTagOption(options, "string") && quotableKind(field.Type) {
+			// encoding/json stores ,string fields as a JSON-encoded string.
+			schema.Properties[name] = &jsonSchema{Type: "string", Nullable: field.Type.Kind() == reflect.Pointer}
+		} else {
+			schema.Properties[name] = schemaForType(field.Type, visiting)
+		}
+		if !hasTagOption(options, "omitempty") && !hasTagOption(options, "omitzero") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+func hasTagOption(options string, option string) bool {
+	return strings.Contains(","+options+",", ","+option+",")
+}
+
+// quotableKind reports whether encoding/json honours the ,string option for t:
+// booleans, numbers and strings, behind at most one pointer.
+func quotableKind(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer && t.Name() == "" {
+		t = t.Elem()
+	}
+	if t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.String:
+		return true
+	}
+	return false
+}
+
+// document renders the schema as a JSON Schema object.
+func (schema *jsonSchema) document() map[string]any {
+	document := map[string]any{}
+	if schema == nil || schema.Type == "" {
+		return document
+	}
+	if schema.Nullable {
+		document["type"] = []string{schema.Type, "null"}
+	} else {
+		document["type"] = schema.Type
+	}
+	if schema.Format != "" {
+		document["format"] = schema.Format
+	}
+	if schema.Unsigned {
+		document["minimum"] = 0
+	}
+	if schema.Items != nil {
+		document["items"] = schema.Items.document()
+	}
+	if schema.Type == "object" && schema.Properties != nil {
+		properties := make(map[string]any, len(schema.Properties))
+		for name, property := range schema.Properties {
+			properties[name] = property.document()
+		}
+		document["properties"] = properties
+	}
+	if len(schema.Required) > 0 {
+		document["required"] = schema.Required
+	}
+	if schema.Closed {
+		document["additionalProperties"] = false
+	} else if schema.Values != nil {
+		document["additionalProperties"] = schema.Values.document()
+	}
+	return document
+}
+
+// validate checks a decoded JSON value (json.Unmarshal into any) against the schema.
+func (schema *jsonSchema) validate(value any) error {
+	return schema.validateAt("$", value)
+}
+
+func (schema *jsonSchema) validateAt(path string, value any) error {
+	if schema == nil || schema.Type == "" {
+		return nil
+	}
+	if value == nil {
+		if schema.Nullable {
+			return nil
+		}
+		return fmt.Errorf("%s: expected %s, got null", path, schema.Type)
+	}
+
+	switch schema.Type {
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return typeMismatch(path, schema.Type, value)
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			return typeMismatch(path, schema.Type, value)
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return typeMismatch(path, schema.Type, value)
+		}
+	case "integer":
+		// math.Trunc, not an int64 round trip: uint64 values above 2^63 overflow int64.
+		number, ok := value.(float64)
+		if !ok || number != math.Trunc(number) {
+			return typeMismatch(path, schema.Type, value)
+		}
+		if schema.Unsigned && number < 0 {
+			return fmt.Errorf("%s: expected non-negative integer, got %v", path, number)
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return typeMismatch(path, schema.Type, value)
+		}
+		for index, item := range items {
+			if err := schema.Items.validateAt(fmt.Sprintf("%s[%d]", path, index), item); err != nil {
+				return err
+			}
+		}
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return typeMismatch(path, schema.Type, value)
+		}
+		return schema.validateObject(path, object)
+	}
+	return nil
+}
+
+func (schema *jsonSchema) validateObject(path string, object map[string]any) error {
+	for _, name := range schema.Required {
+		if _, ok := object[name]; !ok {
+			return fmt.Errorf("%s: missing required property %q", path, name)
+		}
+	}
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		property, known := schema.Properties[key]
+		switch {
+		case known:
+		case schema.Closed:
+			return fmt.Errorf("%s: unexpected property %q", path, key)
+		default:
+			property = schema.Values
+		}
+		if err := property.validateAt(path+"."+key, object[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func typeMismatch(path string, expected string, value any) error {
+	var actual string
+	switch value.(type) {
+	case bool:
+		actual = "boolean"
+	case float64:
+		actual = "number"
+	case string:
+		actual = "string"
+	case []any:
+		actual = "array"
+	case map[string]any:
+		actual = "object"
+	default:
+		actual = fmt.Sprintf("%T", value)
+	}
+	return fmt.Errorf("%s: expected %s, got %s", path, expected, actual)
+}
@@ -0,0 +1,80 @@
+package sdk
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+)
+
+var debugEnabledProvider = func() bool {
+	return os.Getenv("PI_DEBUG") == "1"
+}
+
+// SetDebugEnabledProvider controls the fallback logger used when options carry
+// no Logger: a debug-level text handler on stderr while provider returns true,
+// otherwise discard. It is read once per client start.
+func SetDebugEnabledProvider(provider func() bool) {
+	if provider == nil {
+		debugEnabledProvider = func() bool { return false }
+		return
+	}
+	debugEnabledProvider = provider
+}
+
+func resolveLogger(logger *slog.Logger) *slog.Logger {
+	if logger != nil {
+		return logger.With("component", "pi-golang")
+	}
+	if debugEnabledProvider() {
+		handler := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug})
+		return slog.New(handler).With("component", "pi-golang")
+	}
+	return slog.New(slog.DiscardHandler)
+}
+
+var sensitiveFlagMarkers = []string{"key", "token", "secret", "password", "credential", "auth"}
+
+// redactArgs masks the values of credential-looking flags (--api-key v,
+// --api-key=v) so startup arguments can be logged.
+func redactArgs(args []string) []string {
+	redacted := make([]string, len(args))
+	maskNext := false
+	for index, arg := range args {
+		switch {
+		case maskNext:
+			redacted[index] = "[REDACTED]"
+			maskNext = false
+		case strings.HasPrefix(arg, "-") && sensitiveFlag(arg):
+			if name, _, ok := strings.Cut(arg, "="); ok {
+				redacted[index] = name + "=[REDACTED]"
+			} else {
+				redacted[index] = arg
+				maskNext = true
+			}
+		default:
+			redacted[index] = arg
+		}
+	}
+	return redacted
+}
+
+func sensitiveFlag(arg string) bool {
+	name, _, _ := strings.Cut(strings.ToLower(strings.TrimLeft(arg, "-")), "=")
+	for _, marker := range sensitiveFlagMarkers {
+		if strings.Contains(name, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// envNames lists the variable names of a KEY=value environment; values are
+// never logged.
+func envNames(env []string) []string {
+	names := make([]string, 0, len(env))
+	for _, entry := range env {
+		name, _, _ := strings.Cut(entry, "=")
+		names = append(names, name)
+	}
+	return names
+}
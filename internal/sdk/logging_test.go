@@ -0,0 +1,36 @@
+package sdk
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRedactArgsMasksCredentialFlags(t *testing.T) {
+	args := []string{
+		"--mode", "rpc",
+		"--api-key", "sk-secret",
+		"--oauth-token=tok-secret",
+		"--model", "claude",
+		"--auth-file", "/home/me/auth.json",
+	}
+	want := []string{
+		"--mode", "rpc",
+		"--api-key", "[REDACTED]",
+		"--oauth-token=[REDACTED]",
+		"--model", "claude",
+		"--auth-file", "[REDACTED]",
+	}
+	if got := redactArgs(args); !slices.Equal(got, want) {
+		t.Fatalf("unexpected redaction:\n got %q\nwant %q", got, want)
+	}
+	if args[3] != "sk-secret" {
+		t.Fatal("redactArgs must not modify its input")
+	}
+}
+
+func TestEnvNamesOmitsValues(t *testing.T) {
+	got := envNames([]string{"ANTHROPIC_API_KEY=sk-secret", "PATH=/bin", "EMPTY="})
+	if want := []string{"ANTHROPIC_API_KEY", "PATH", "EMPTY"}; !slices.Equal(got, want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,48 @@
+package sdk
+
+import "strings"
+
+// managedExtension is an SDK-generated extension bundle shipped with the pi process.
+type managedExtension interface {
+	arguments() []string
+	injectEnvironment(values map[string]string)
+	cleanup() error
+}
+
+func createManagedExtensions(config startConfig) (extensions []managedExtension, err error) {
+	defer func() {
+		if err != nil {
+			cleanupManagedExtensions(extensions)
+			extensions = nil
+		}
+	}()
+
+	if strings.TrimSpace(config.compactionPrompt) != "" {
+		hook, err := createManagedCompactionHook(config.compactionPrompt)
+		if err != nil {
+			return extensions, err
+		}
+		extensions = append(extensions, hook)
+	}
+	if len(config.tools) > 0 {
+		bridge, err := createManagedToolBridge(config.tools)
+		if err != nil {
+			return extensions, err
+		}
+		extensions = append(extensions, bridge)
+	}
+	if config.approveToolCall != nil {
+		gate, err := createManagedApprovalGate()
+		if err != nil {
+			return extensions, err
+		}
+		extensions = append(extensions, gate)
+	}
+	return extensions, nil
+}
+
+func cleanupManagedExtensions(extensions []managedExtension) {
+	for _, extension := range extensions {
+		_ = extension.cleanup()
+	}
+}
@@ -0,0 +1,204 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/joshp123/pi-golang/internal/rpc"
+	"github.com/joshp123/pi-golang/internal/testsupport"
+)
+
+func TestSetModelCommandRequiresProviderAndModel(t *testing.T) {
+	if _, err := setModelCommand("", "claude-opus-4-5"); err == nil {
+		t.Fatal("expected missing provider error")
+	}
+	if _, err := setModelCommand("anthropic", " "); err == nil {
+		t.Fatal("expected missing model id error")
+	}
+
+	command, err := setModelCommand(" anthropic ", " claude-opus-4-5 ")
+	if err != nil {
+		t.Fatalf("setModelCommand returned error: %v", err)
+	}
+	if command["type"] != rpc.CommandSetModel || command["provider"] != "anthropic" || command["modelId"] != "claude-opus-4-5" {
+		t.Fatalf("unexpected set_model command: %+v", command)
+	}
+}
+
+func TestDecodeModelCycleNullMeansSingleModel(t *testing.T) {
+	result, err := decodeModelCycle(json.RawMessage(`null`))
+	if err != nil {
+		t.Fatalf("decodeModelCycle returned error: %v", err)
+	}
+	if result.Model != nil {
+		t.Fatalf("expected nil model for null cycle result, got %+v", result.Model)
+	}
+}
+
+func TestDecodeAvailableModelsRequiresIdentity(t *testing.T) {
+	_, err := decodeAvailableModels(json.RawMessage(`{"models":[{"id":"m1"}]}`))
+	if !errors.Is(err, ErrProtocolViolation) {
+		t.Fatalf("expected protocol violation for missing provider, got %v", err)
+	}
+}
+
+func TestModelMirrorMethods(t *testing.T) {
+	testsupport.SetupFakePI(t, "happy")
+
+	client, err := StartOneShot(testModelOneShotOptions())
+	if err != nil {
+		t.Fatalf("StartOneShot returned error: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	models, err := client.GetAvailableModels(ctx)
+	if err != nil {
+		t.Fatalf("GetAvailableModels returned error: %v", err)
+	}
+	if len(models) != 2 {
+		t.Fatalf("expected 2 models, got %d", len(models))
+	}
+
+	model, err := client.SetModel(ctx, "anthropic", "claude-haiku-4-5")
+	if err != nil {
+		t.Fatalf("SetModel returned error: %v", err)
+	}
+	if model.ID != "claude-haiku-4-5" || model.Provider != "anthropic" {
+		t.Fatalf("unexpected model: %+v", model)
+	}
+
+	_, err = client.SetModel(ctx, "anthropic", "missing-model")
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Command != rpc.CommandSetModel {
+		t.Fatalf("expected set_model RPCError, got %v", err)
+	}
+
+	cycled, err := client.CycleModel(ctx)
+	if err != nil {
+		t.Fatalf("CycleModel returned error: %v", err)
+	}
+	if cycled.Model == nil || cycled.Model.ID != "claude-haiku-4-5" {
+		t.Fatalf("unexpected cycle result: %+v", cycled)
+	}
+}
+
+func TestSwitchModeChangesModelAndThinking(t *testing.T) {
+	testsupport.SetupFakePI(t, "happy")
+
+	client, err := StartOneShot(testModelOneShotOptions())
+	if err != nil {
+		t.Fatalf("StartOneShot returned error: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	model, err := client.SwitchMode(ctx, ModeFast)
+	if err != nil {
+		t.Fatalf("SwitchMode returned error: %v", err)
+	}
+	if model.ID != DefaultFastModel {
+		t.Fatalf("expected model %q, got %q", DefaultFastModel, model.ID)
+	}
+
+	state, err := client.GetState(ctx)
+	if err != nil {
+		t.Fatalf("GetState returned error: %v", err)
+	}
+	if state.Model == nil || state.Model.ID != DefaultFastModel {
+		t.Fatalf("expected state model %q, got %+v", DefaultFastModel, state.Model)
+	}
+	if state.ThinkingLevel != DefaultDumbThinking {
+		t.Fatalf("expected thinking %q, got %q", DefaultDumbThinking, state.ThinkingLevel)
+	}
+}
+
+func TestSwitchModeValidatesTargetProviderAuth(t *testing.T) {
+	testsupport.SetupFakePI(t, "happy")
+
+	client, err := StartOneShot(testModelOneShotOptions())
+	if err != nil {
+		t.Fatalf("StartOneShot returned error: %v", err)
+	}
+	defer client.Close()
+
+	_, err = client.SwitchMode(context.Background(), ModeCoding)
+	var authErr *MissingProviderAuthError
+	if !errors.As(err, &authErr) {
+		t.Fatalf("expected MissingProviderAuthError, got %v", err)
+	}
+	if authErr.Provider != DefaultCodingProvider {
+		t.Fatalf("unexpected provider in auth error: %q", authErr.Provider)
+	}
+
+	if _, err := client.SwitchMode(context.Background(), ModeDragons); err == nil {
+		t.Fatal("expected dragons mode to be rejected")
+	}
+}
+
+func TestSetThinkingLevelCommandValidatesLevel(t *testing.T) {
+	if _, err := setThinkingLevelCommand(""); err == nil {
+		t.Fatal("expected missing level error")
+	}
+	if _, err := setThinkingLevelCommand(ThinkingLevel("extreme")); err == nil {
+		t.Fatal("expected invalid level error")
+	}
+	command, err := setThinkingLevelCommand(ThinkingLevelXHigh)
+	if err != nil {
+		t.Fatalf("setThinkingLevelCommand returned error: %v", err)
+	}
+	if command["type"] != rpc.CommandSetThinkingLevel || command["level"] != "xhigh" {
+		t.Fatalf("unexpected set_thinking_level command: %+v", command)
+	}
+}
+
+func TestDecodeThinkingLevelCycleNullMeansUnsupported(t *testing.T) {
+	level, err := decodeThinkingLevelCycle(json.RawMessage(`null`))
+	if err != nil {
+		t.Fatalf("decodeThinkingLevelCycle returned error: %v", err)
+	}
+	if level != "" {
+		t.Fatalf("expected empty level, got %q", level)
+	}
+	if _, err := decodeThinkingLevelCycle(json.RawMessage(`{"level":"extreme"}`)); !errors.Is(err, ErrProtocolViolation) {
+		t.Fatalf("expected protocol violation for unknown level, got %v", err)
+	}
+}
+
+func TestThinkingLevelMirrorMethods(t *testing.T) {
+	testsupport.SetupFakePI(t, "happy")
+
+	client, err := StartOneShot(testModelOneShotOptions())
+	if err != nil {
+		t.Fatalf("StartOneShot returned error: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	if err := client.SetThinkingLevel(ctx, DefaultDumbThinking); err != nil {
+		t.Fatalf("SetThinkingLevel returned error: %v", err)
+	}
+	level, err := client.CycleThinkingLevel(ctx)
+	if err != nil {
+		t.Fatalf("CycleThinkingLevel returned error: %v", err)
+	}
+	if level != ThinkingLevelMedium {
+		t.Fatalf("expected %q after cycling from low, got %q", ThinkingLevelMedium, level)
+	}
+
+	state, err := client.GetState(ctx)
+	if err != nil {
+		t.Fatalf("GetState returned error: %v", err)
+	}
+	if state.ThinkingLevel != ThinkingLevelMedium {
+		t.Fatalf("expected state thinking %q, got %q", ThinkingLevelMedium, state.ThinkingLevel)
+	}
+}
+
+func testModelOneShotOptions() OneShotOptions {
+	options := DefaultOneShotOptions()
+	options.Auth.Anthropic.APIKey = Credential{Value: "test-key"}
+	return options
+}
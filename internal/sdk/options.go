@@ -2,9 +2,12 @@ package sdk
 
 import (
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 const (
@@ -97,6 +100,24 @@ type SessionOptions struct {
 	SeedAuthFromHome   bool
 	Skills             SkillsOptions
 	CompactionPrompt   string
+	UIHandler          UIHandler
+	UITimeout          time.Duration
+	Tools              []ToolDefinition
+	ApproveToolCall    ApproveToolCallFunc
+	// RunQueue serialises concurrent Run/RunDetailed/Stream calls FIFO instead of
+	// failing with ErrRunInProgress.
+	RunQueue bool
+	// StderrLimit caps the pi stderr kept for Stderr/StderrLines (default 1 MiB);
+	// older output is discarded. It also bounds SubscribeStderr lines.
+	StderrLimit int
+	// StderrWriter receives pi stderr verbatim and StderrLogger one record per
+	// line, as it arrives. Both are called inline: a slow sink stalls pi's stderr.
+	StderrWriter io.Writer
+	StderrLogger *slog.Logger
+	// Logger receives the client's requests, events, subscription drops and
+	// process lifecycle. When nil, PI_DEBUG=1 logs at debug level to stderr;
+	// otherwise logs are discarded.
+	Logger *slog.Logger
 }
 
 type OneShotOptions struct {
@@ -111,6 +132,24 @@ type OneShotOptions struct {
 	SeedAuthFromHome   bool
 	Skills             SkillsOptions
 	CompactionPrompt   string
+	UIHandler          UIHandler
+	UITimeout          time.Duration
+	Tools              []ToolDefinition
+	ApproveToolCall    ApproveToolCallFunc
+	// RunQueue serialises concurrent Run/RunDetailed/Stream calls FIFO instead of
+	// failing with ErrRunInProgress.
+	RunQueue bool
+	// StderrLimit caps the pi stderr kept for Stderr/StderrLines (default 1 MiB);
+	// older output is discarded. It also bounds SubscribeStderr lines.
+	StderrLimit int
+	// StderrWriter receives pi stderr verbatim and StderrLogger one record per
+	// line, as it arrives. Both are called inline: a slow sink stalls pi's stderr.
+	StderrWriter io.Writer
+	StderrLogger *slog.Logger
+	// Logger receives the client's requests, events, subscription drops and
+	// process lifecycle. When nil, PI_DEBUG=1 logs at debug level to stderr;
+	// otherwise logs are discarded.
+	Logger *slog.Logger
 }
 
 func DefaultSessionOptions() SessionOptions {
@@ -146,11 +185,25 @@ func normalizeSessionOptions(options SessionOptions) (SessionOptions, error) {
 	options.SessionName = strings.TrimSpace(options.SessionName)
 	options.Auth = trimProviderAuth(options.Auth)
 	options.Environment = cloneStringMap(options.Environment)
+	if options.UITimeout < 0 {
+		return options, fmt.Errorf("ui timeout must be >= 0")
+	}
+	if options.StderrLimit < 0 {
+		return options, fmt.Errorf("stderr limit must be >= 0")
+	}
+	if options.StderrLimit == 0 {
+		options.StderrLimit = defaultStderrLimit
+	}
 	normalizedSkills, err := normalizeSkillsOptions(options.Skills, options.WorkDir)
 	if err != nil {
 		return options, err
 	}
 	options.Skills = normalizedSkills
+	normalizedTools, err := normalizeToolDefinitions(options.Tools)
+	if err != nil {
+		return options, err
+	}
+	options.Tools = normalizedTools
 	return options, nil
 }
 
@@ -168,11 +221,25 @@ func normalizeOneShotOptions(options OneShotOptions) (OneShotOptions, error) {
 	options.WorkDir = strings.TrimSpace(options.WorkDir)
 	options.Auth = trimProviderAuth(options.Auth)
 	options.Environment = cloneStringMap(options.Environment)
+	if options.UITimeout < 0 {
+		return options, fmt.Errorf("ui timeout must be >= 0")
+	}
+	if options.StderrLimit < 0 {
+		return options, fmt.Errorf("stderr limit must be >= 0")
+	}
+	if options.StderrLimit == 0 {
+		options.StderrLimit = defaultStderrLimit
+	}
 	normalizedSkills, err := normalizeSkillsOptions(options.Skills, options.WorkDir)
 	if err != nil {
 		return options, err
 	}
 	options.Skills = normalizedSkills
+	normalizedTools, err := normalizeToolDefinitions(options.Tools)
+	if err != nil {
+		return options, err
+	}
+	options.Tools = normalizedTools
 	return options, nil
 }
 
@@ -5,24 +5,28 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"io"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/joshp123/pi-golang/internal/rpc"
 )
 
-func (client *Client) captureStderr(stderr io.Reader) {
-	buffer := make([]byte, 4096)
-	for {
-		read, err := stderr.Read(buffer)
-		if read > 0 {
-			client.appendStderr(buffer[:read])
-		}
-		if err != nil {
-			return
-		}
-	}
+// processExitSettleTimeout bounds how long markProcessDied waits for the exit
+// status (and so the full stderr) before reporting.
+var processExitSettleTimeout = 250 * time.Millisecond
+
+// processExitStderrLines is how much stderr ProcessExitError keeps.
+const processExitStderrLines = 20
+
+type stderrWriter struct {
+	client *Client
+}
+
+func (writer stderrWriter) Write(chunk []byte) (int, error) {
+	writer.client.appendStderr(chunk)
+	return len(chunk), nil
 }
 
 func (client *Client) readStdout(stdout io.Reader) {
@@ -48,12 +52,14 @@ func (client *Client) dispatchEvents() {
 		if !ok {
 			return
 		}
+		client.logger.Debug("dispatch event", "event_type", event.Type)
 		client.events.Publish(event)
 	}
 }
 
 func (client *Client) waitForProcess() {
 	waitErr := client.process.Wait()
+	client.finishStderr()
 	close(client.waitDone)
 
 	select {
@@ -70,33 +76,39 @@ func (client *Client) waitForProcess() {
 }
 
 func (client *Client) handleLine(line []byte) {
+	receivedAt := time.Now()
 	var envelope struct {
 		Type string `json:"type"`
 		ID   string `json:"id,omitempty"`
 	}
 	if err := json.Unmarshal(line, &envelope); err != nil {
-		client.enqueueEvent(Event{Type: rpc.EventParseError, Raw: append([]byte(nil), line...)})
+		client.enqueueEvent(Event{Type: rpc.EventParseError, Raw: append([]byte(nil), line...), ReceivedAt: receivedAt})
 		return
 	}
 	if strings.TrimSpace(envelope.Type) == "" {
-		client.enqueueEvent(Event{Type: rpc.EventParseError, Raw: append([]byte(nil), line...)})
+		client.enqueueEvent(Event{Type: rpc.EventParseError, Raw: append([]byte(nil), line...), ReceivedAt: receivedAt})
 		return
 	}
 
 	if envelope.Type == rpc.EventResponse {
 		response, err := decodeRPCResponse(line)
 		if err != nil {
-			client.enqueueEvent(Event{Type: rpc.EventResponseParseError, Raw: append([]byte(nil), line...)})
+			client.enqueueEvent(Event{Type: rpc.EventResponseParseError, Raw: append([]byte(nil), line...), ReceivedAt: receivedAt})
 			return
 		}
 		if client.requests.Resolve(response) {
 			return
 		}
-		client.enqueueEvent(Event{Type: rpc.EventResponse, Raw: append([]byte(nil), line...)})
+		client.enqueueEvent(Event{Type: rpc.EventResponse, Raw: append([]byte(nil), line...), ReceivedAt: receivedAt})
 		return
 	}
 
-	client.enqueueEvent(Event{Type: envelope.Type, Raw: append([]byte(nil), line...)})
+	event := Event{Type: envelope.Type, Raw: append([]byte(nil), line...), ReceivedAt: receivedAt}
+	if event.Type == EventTypeExtensionUIRequest {
+		client.handleExtensionUIRequest(event)
+		return
+	}
+	client.enqueueEvent(event)
 }
 
 func (client *Client) enqueueEvent(event Event) {
@@ -106,7 +118,24 @@ func (client *Client) enqueueEvent(event Event) {
 	_ = client.eventQueue.Push(event)
 }
 
+// kill terminates the process without the graceful Close path; pending and
+// later calls fail with ErrProcessDied.
+func (client *Client) kill() {
+	if client.process != nil && client.process.Process != nil {
+		client.logger.Warn("killing pi process")
+		client.killed.Store(true)
+		_ = client.process.Process.Kill()
+	}
+}
+
 func (client *Client) markProcessDied(cause error) {
+	// stdout usually hits EOF before Wait returns; give the exit status a moment
+	// to land.
+	select {
+	case <-client.waitDone:
+	case <-time.After(processExitSettleTimeout):
+	}
+
 	client.processErrOnce.Do(func() {
 		select {
 		case <-client.closed:
@@ -114,17 +143,50 @@ func (client *Client) markProcessDied(cause error) {
 		default:
 		}
 
-		processErr := ErrProcessDied
-		if cause != nil && !errors.Is(cause, io.EOF) {
-			processErr = fmt.Errorf("%w: %v", ErrProcessDied, cause)
-		}
-
+		processErr := client.processExitError(cause)
+		client.logger.Error("pi process died",
+			"exit_code", processErr.ExitCode,
+			"signal", processErr.Signal,
+			"sdk_initiated", processErr.SDKInitiated,
+			"uptime", processErr.Uptime,
+			"in_flight_request_id", processErr.InFlightRequestID,
+			"stderr_tail", processErr.StderrTail,
+			"error", processErr.Err,
+		)
 		client.requests.MarkProcessDied(processErr)
-		client.events.ProcessDied(newProcessDiedEvent(cause))
+		client.events.ProcessDied(newProcessDiedEvent(processErr))
 		client.stopEventDispatch()
 	})
 }
 
+func (client *Client) processExitError(cause error) *ProcessExitError {
+	processErr := &ProcessExitError{
+		ExitCode:          -1,
+		SDKInitiated:      client.killed.Load(),
+		StderrTail:        client.StderrLines(processExitStderrLines),
+		Uptime:            time.Since(client.startedAt),
+		InFlightRequestID: client.requests.LatestPending(),
+	}
+	if cause != nil && !errors.Is(cause, io.EOF) {
+		processErr.Err = cause
+	}
+
+	select {
+	case <-client.waitDone:
+	default:
+		return processErr
+	}
+	state := client.process.ProcessState
+	if state == nil {
+		return processErr
+	}
+	processErr.ExitCode = state.ExitCode()
+	if status, ok := state.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		processErr.Signal = status.Signal().String()
+	}
+	return processErr
+}
+
 func (client *Client) closeAll(processErr error) {
 	client.requests.Close(processErr)
 	client.events.Close()
@@ -137,9 +199,28 @@ func (client *Client) currentProcessError() error {
 
 func newProcessDiedEvent(cause error) Event {
 	payload := map[string]any{"type": EventTypeProcessDied}
-	if cause != nil && !errors.Is(cause, io.EOF) {
+	var exitErr *ProcessExitError
+	if errors.As(cause, &exitErr) {
+		payload["error"] = exitErr.Error()
+		if exitErr.ExitCode >= 0 {
+			payload["exitCode"] = exitErr.ExitCode
+		}
+		if exitErr.Signal != "" {
+			payload["signal"] = exitErr.Signal
+		}
+		if exitErr.SDKInitiated {
+			payload["sdkInitiated"] = true
+		}
+		if len(exitErr.StderrTail) > 0 {
+			payload["stderrTail"] = exitErr.StderrTail
+		}
+		payload["uptimeMs"] = exitErr.Uptime.Milliseconds()
+		if exitErr.InFlightRequestID != "" {
+			payload["inFlightRequestId"] = exitErr.InFlightRequestID
+		}
+	} else if cause != nil && !errors.Is(cause, io.EOF) {
 		payload["error"] = cause.Error()
 	}
 	raw, _ := json.Marshal(payload)
-	return Event{Type: EventTypeProcessDied, Raw: raw}
+	return Event{Type: EventTypeProcessDied, Raw: raw, ReceivedAt: time.Now()}
 }
@@ -86,6 +86,97 @@ func getCommandsCommand() rpc.Command {
 	return rpc.Command{"type": rpc.CommandGetCommands}
 }
 
+func setModelCommand(provider string, modelID string) (rpc.Command, error) {
+	provider = strings.TrimSpace(provider)
+	modelID = strings.TrimSpace(modelID)
+	if provider == "" {
+		return nil, errors.New("provider is required")
+	}
+	if modelID == "" {
+		return nil, errors.New("model id is required")
+	}
+	return rpc.Command{
+		"type":     rpc.CommandSetModel,
+		"provider": provider,
+		"modelId":  modelID,
+	}, nil
+}
+
+func cycleModelCommand() rpc.Command {
+	return rpc.Command{"type": rpc.CommandCycleModel}
+}
+
+func getAvailableModelsCommand() rpc.Command {
+	return rpc.Command{"type": rpc.CommandGetAvailableModels}
+}
+
+func setThinkingLevelCommand(level ThinkingLevel) (rpc.Command, error) {
+	if err := validateThinkingLevel(level); err != nil {
+		return nil, err
+	}
+	return rpc.Command{
+		"type":  rpc.CommandSetThinkingLevel,
+		"level": string(level),
+	}, nil
+}
+
+func cycleThinkingLevelCommand() rpc.Command {
+	return rpc.Command{"type": rpc.CommandCycleThinkingLevel}
+}
+
+func bashCommand(shellCommand string) (rpc.Command, error) {
+	if strings.TrimSpace(shellCommand) == "" {
+		return nil, errors.New("bash command is required")
+	}
+	return rpc.Command{"type": rpc.CommandBash, "command": shellCommand}, nil
+}
+
+func abortBashCommand() rpc.Command {
+	return rpc.Command{"type": rpc.CommandAbortBash}
+}
+
+func switchSessionCommand(sessionPath string) (rpc.Command, error) {
+	if strings.TrimSpace(sessionPath) == "" {
+		return nil, errors.New("session path is required")
+	}
+	return rpc.Command{"type": rpc.CommandSwitchSession, "sessionPath": strings.TrimSpace(sessionPath)}, nil
+}
+
+func forkCommand(entryID string) (rpc.Command, error) {
+	if strings.TrimSpace(entryID) == "" {
+		return nil, errors.New("entry id is required")
+	}
+	return rpc.Command{"type": rpc.CommandFork, "entryId": strings.TrimSpace(entryID)}, nil
+}
+
+func getForkMessagesCommand() rpc.Command {
+	return rpc.Command{"type": rpc.CommandGetForkMessages}
+}
+
+func getMessagesCommand() rpc.Command {
+	return rpc.Command{"type": rpc.CommandGetMessages}
+}
+
+func getSessionStatsCommand() rpc.Command {
+	return rpc.Command{"type": rpc.CommandGetSessionStats}
+}
+
+func extensionUIResponseCommand(request ExtensionUIRequest, answer uiAnswer) rpc.Command {
+	command := rpc.Command{
+		"type": rpc.CommandExtensionUIResponse,
+		"id":   request.ID,
+	}
+	switch {
+	case answer.cancelled:
+		command["cancelled"] = true
+	case request.Method == UIMethodConfirm:
+		command["confirmed"] = answer.confirmed
+	default:
+		command["value"] = answer.value
+	}
+	return command
+}
+
 type slashCommand struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
@@ -124,9 +215,92 @@ func decodeSessionState(data json.RawMessage) (SessionState, error) {
 	return state, nil
 }
 
-func decodeNewSessionCancelled(data json.RawMessage) (bool, error) {
+func decodeModelInfo(data json.RawMessage) (ModelInfo, error) {
 	if len(data) == 0 || string(data) == "null" {
-		return false, fmt.Errorf("%w: new_session missing response data", ErrProtocolViolation)
+		return ModelInfo{}, fmt.Errorf("%w: set_model missing response data", ErrProtocolViolation)
+	}
+	var model ModelInfo
+	if err := json.Unmarshal(data, &model); err != nil {
+		return ModelInfo{}, err
+	}
+	if err := requireModelIdentity("set_model", model); err != nil {
+		return ModelInfo{}, err
+	}
+	return model, nil
+}
+
+func decodeModelCycle(data json.RawMessage) (ModelCycleResult, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return ModelCycleResult{}, nil
+	}
+	var payload struct {
+		Model         *ModelInfo    `json:"model"`
+		ThinkingLevel ThinkingLevel `json:"thinkingLevel"`
+		IsScoped      bool          `json:"isScoped"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return ModelCycleResult{}, err
+	}
+	if payload.Model == nil {
+		return ModelCycleResult{}, fmt.Errorf("%w: cycle_model missing model", ErrProtocolViolation)
+	}
+	if err := requireModelIdentity("cycle_model", *payload.Model); err != nil {
+		return ModelCycleResult{}, err
+	}
+	return ModelCycleResult{
+		Model:         payload.Model,
+		ThinkingLevel: payload.ThinkingLevel,
+		IsScoped:      payload.IsScoped,
+	}, nil
+}
+
+func decodeThinkingLevelCycle(data json.RawMessage) (ThinkingLevel, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return "", nil
+	}
+	var payload struct {
+		Level ThinkingLevel `json:"level"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return "", err
+	}
+	if err := validateThinkingLevel(payload.Level); err != nil {
+		return "", fmt.Errorf("%w: cycle_thinking_level: %v", ErrProtocolViolation, err)
+	}
+	return payload.Level, nil
+}
+
+func decodeAvailableModels(data json.RawMessage) ([]ModelInfo, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, fmt.Errorf("%w: get_available_models missing response data", ErrProtocolViolation)
+	}
+	var payload struct {
+		Models []ModelInfo `json:"models"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, err
+	}
+	for _, model := range payload.Models {
+		if err := requireModelIdentity("get_available_models", model); err != nil {
+			return nil, err
+		}
+	}
+	return payload.Models, nil
+}
+
+func requireModelIdentity(command string, model ModelInfo) error {
+	if strings.TrimSpace(model.Provider) == "" {
+		return fmt.Errorf("%w: %s model missing provider", ErrProtocolViolation, command)
+	}
+	if strings.TrimSpace(model.ID) == "" {
+		return fmt.Errorf("%w: %s model missing id", ErrProtocolViolation, command)
+	}
+	return nil
+}
+
+func decodeCancelled(command string, data json.RawMessage) (bool, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return false, fmt.Errorf("%w: %s missing response data", ErrProtocolViolation, command)
 	}
 
 	var payload struct {
@@ -146,6 +320,110 @@ func decodeCompactResult(data json.RawMessage) (CompactResult, error) {
 	return result, nil
 }
 
+func decodeBashResult(data json.RawMessage) (BashResult, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return BashResult{}, fmt.Errorf("%w: bash missing response data", ErrProtocolViolation)
+	}
+	var result BashResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return BashResult{}, err
+	}
+	return result, nil
+}
+
+func decodeForkResult(data json.RawMessage) (ForkResult, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return ForkResult{}, fmt.Errorf("%w: fork missing response data", ErrProtocolViolation)
+	}
+	var result ForkResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return ForkResult{}, err
+	}
+	return result, nil
+}
+
+func decodeForkMessages(data json.RawMessage) ([]ForkMessage, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, fmt.Errorf("%w: get_fork_messages missing response data", ErrProtocolViolation)
+	}
+	var payload struct {
+		Messages []ForkMessage `json:"messages"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, err
+	}
+	for _, message := range payload.Messages {
+		if strings.TrimSpace(message.EntryID) == "" {
+			return nil, fmt.Errorf("%w: get_fork_messages entry missing entryId", ErrProtocolViolation)
+		}
+	}
+	return payload.Messages, nil
+}
+
+func decodeMessages(data json.RawMessage) ([]AgentMessage, error) {
+	var payload struct {
+		Messages []AgentMessage `json:"messages"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, err
+	}
+	if payload.Messages == nil {
+		return nil, fmt.Errorf("%w: get_messages missing messages", ErrProtocolViolation)
+	}
+	for _, message := range payload.Messages {
+		if strings.TrimSpace(message.Role) == "" {
+			return nil, fmt.Errorf("%w: get_messages message missing role", ErrProtocolViolation)
+		}
+	}
+	return payload.Messages, nil
+}
+
+func decodeSessionStats(data json.RawMessage) (SessionStats, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return SessionStats{}, fmt.Errorf("%w: get_session_stats missing response data", ErrProtocolViolation)
+	}
+	var payload struct {
+		SessionFile       string `json:"sessionFile"`
+		SessionID         string `json:"sessionId"`
+		UserMessages      int    `json:"userMessages"`
+		AssistantMessages int    `json:"assistantMessages"`
+		ToolCalls         int    `json:"toolCalls"`
+		ToolResults       int    `json:"toolResults"`
+		TotalMessages     int    `json:"totalMessages"`
+		Tokens            *struct {
+			Input      int `json:"input"`
+			Output     int `json:"output"`
+			CacheRead  int `json:"cacheRead"`
+			CacheWrite int `json:"cacheWrite"`
+			Total      int `json:"total"`
+		} `json:"tokens"`
+		Cost float64 `json:"cost"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return SessionStats{}, err
+	}
+	if payload.Tokens == nil {
+		return SessionStats{}, fmt.Errorf("%w: get_session_stats missing tokens", ErrProtocolViolation)
+	}
+	return SessionStats{
+		SessionFile:       payload.SessionFile,
+		SessionID:         payload.SessionID,
+		UserMessages:      payload.UserMessages,
+		AssistantMessages: payload.AssistantMessages,
+		ToolCalls:         payload.ToolCalls,
+		ToolResults:       payload.ToolResults,
+		TotalMessages:     payload.TotalMessages,
+		Usage: Usage{
+			Input:       payload.Tokens.Input,
+			Output:      payload.Tokens.Output,
+			CacheRead:   payload.Tokens.CacheRead,
+			CacheWrite:  payload.Tokens.CacheWrite,
+			TotalTokens: payload.Tokens.Total,
+			Cost:        &Cost{Total: payload.Cost},
+		},
+	}, nil
+}
+
 func decodeExportPath(data json.RawMessage) (string, error) {
 	var payload struct {
 		Path string `json:"path"`
@@ -188,6 +466,17 @@ func validatePromptRequest(request PromptRequest, allowStreamingBehavior bool) e
 	return validateImages(request.Images)
 }
 
+func validateThinkingLevel(level ThinkingLevel) error {
+	switch level {
+	case ThinkingLevelOff, ThinkingLevelMinimal, ThinkingLevelLow, ThinkingLevelMedium, ThinkingLevelHigh, ThinkingLevelXHigh:
+		return nil
+	case "":
+		return errors.New("thinking level is required")
+	default:
+		return fmt.Errorf("invalid thinking level %q", level)
+	}
+}
+
 func validateImages(images []ImageContent) error {
 	for index, image := range images {
 		if strings.TrimSpace(image.Data) == "" {
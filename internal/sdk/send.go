@@ -12,6 +12,7 @@ import (
 )
 
 var defaultRequestTimeout = 2 * time.Minute
+var defaultBashAbortTimeout = 2 * time.Second
 
 func (client *Client) send(ctx context.Context, command rpc.Command) (rpc.Response, error) {
 	commandType, err := commandTypeOf(command)
@@ -19,7 +20,7 @@ func (client *Client) send(ctx context.Context, command rpc.Command) (rpc.Respon
 		return rpc.Response{}, err
 	}
 
-	ctx, cancel, err := withDefaultRequestTimeout(ctx)
+	ctx, cancel, err := withDefaultRequestTimeout(ctx, commandType)
 	if err != nil {
 		return rpc.Response{}, err
 	}
@@ -42,21 +43,26 @@ func (client *Client) send(ctx context.Context, command rpc.Command) (rpc.Respon
 	if err := client.requests.Register(requestID, responseChan); err != nil {
 		return rpc.Response{}, err
 	}
+	sentAt := time.Now()
+	logger := client.logger.With("command", commandType, "request_id", requestID)
+	logger.Debug("rpc request")
 
-	client.writeLock.Lock()
-	_, writeErr := client.stdin.Write(append(payload, '\n'))
-	client.writeLock.Unlock()
-	if writeErr != nil {
+	if writeErr := client.writeFrame(payload); writeErr != nil {
 		client.requests.Drop(requestID)
 		if err := client.terminalError(); err != nil {
 			return rpc.Response{}, err
 		}
+		logger.Warn("rpc request write failed", "error", writeErr)
 		return rpc.Response{}, fmt.Errorf("write %s command: %w", commandType, writeErr)
 	}
 
 	select {
 	case <-ctx.Done():
 		client.requests.Drop(requestID)
+		logger.Debug("rpc request abandoned", "latency", time.Since(sentAt), "error", ctx.Err())
+		if commandType == rpc.CommandBash {
+			client.abortBashBestEffort()
+		}
 		return rpc.Response{}, ctx.Err()
 	case <-client.closed:
 		client.requests.Drop(requestID)
@@ -71,18 +77,41 @@ func (client *Client) send(ctx context.Context, command rpc.Command) (rpc.Respon
 			}
 			return rpc.Response{}, fmt.Errorf("%w: closed response channel for request %s", ErrProtocolViolation, requestID)
 		}
+		latency := time.Since(sentAt)
 		if !response.Success {
+			logger.Warn("rpc response", "latency", latency, "success", false, "error", response.Error)
 			return response, rpcErrorFromResponse(response)
 		}
+		logger.Debug("rpc response", "latency", latency, "success", true)
 		return response, nil
 	}
 }
 
-func withDefaultRequestTimeout(ctx context.Context) (context.Context, context.CancelFunc, error) {
+// abortBashBestEffort stops a bash command whose caller stopped waiting.
+func (client *Client) abortBashBestEffort() {
+	if err := client.terminalError(); err != nil {
+		return
+	}
+	abortCtx, cancel := context.WithTimeout(context.Background(), defaultBashAbortTimeout)
+	defer cancel()
+	_ = client.AbortBash(abortCtx)
+}
+
+// writeFrame writes one newline-delimited JSON frame to pi stdin.
+func (client *Client) writeFrame(payload []byte) error {
+	client.writeLock.Lock()
+	defer client.writeLock.Unlock()
+	_, err := client.stdin.Write(append(payload, '\n'))
+	return err
+}
+
+// withDefaultRequestTimeout bounds requests whose ctx has no deadline. bash is
+// exempt: it runs as long as the shell command, so only the caller's ctx bounds it.
+func withDefaultRequestTimeout(ctx context.Context, commandType string) (context.Context, context.CancelFunc, error) {
 	if ctx == nil {
 		return nil, nil, ErrNilContext
 	}
-	if _, hasDeadline := ctx.Deadline(); hasDeadline {
+	if _, hasDeadline := ctx.Deadline(); hasDeadline || commandType == rpc.CommandBash {
 		return ctx, func() {}, nil
 	}
 	timedCtx, cancel := context.WithTimeout(ctx, defaultRequestTimeout)
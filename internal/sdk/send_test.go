@@ -11,14 +11,14 @@ import (
 )
 
 func TestWithDefaultRequestTimeoutRejectsNilContext(t *testing.T) {
-	_, _, err := withDefaultRequestTimeout(nil)
+	_, _, err := withDefaultRequestTimeout(nil, rpc.CommandGetState)
 	if !errors.Is(err, ErrNilContext) {
 		t.Fatalf("expected ErrNilContext, got %v", err)
 	}
 }
 
 func TestWithDefaultRequestTimeoutAddsDeadlineWhenMissing(t *testing.T) {
-	ctx, cancel, err := withDefaultRequestTimeout(context.Background())
+	ctx, cancel, err := withDefaultRequestTimeout(context.Background(), rpc.CommandGetState)
 	if err != nil {
 		t.Fatalf("withDefaultRequestTimeout returned error: %v", err)
 	}
@@ -33,6 +33,18 @@ func TestWithDefaultRequestTimeoutAddsDeadlineWhenMissing(t *testing.T) {
 	}
 }
 
+func TestWithDefaultRequestTimeoutExemptsBash(t *testing.T) {
+	ctx, cancel, err := withDefaultRequestTimeout(context.Background(), rpc.CommandBash)
+	if err != nil {
+		t.Fatalf("withDefaultRequestTimeout returned error: %v", err)
+	}
+	defer cancel()
+
+	if _, ok := ctx.Deadline(); ok {
+		t.Fatal("expected bash to run without a default deadline")
+	}
+}
+
 func TestValidatePromptRequestRejectsInvalidStreamingBehavior(t *testing.T) {
 	err := validatePromptRequest(PromptRequest{Message: "hello", StreamingBehavior: StreamingBehavior("nope")}, true)
 	if err == nil {
@@ -0,0 +1,95 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/joshp123/pi-golang/internal/rpc"
+	"github.com/joshp123/pi-golang/internal/testsupport"
+)
+
+func TestSessionBranchingCommandsValidateInput(t *testing.T) {
+	if _, err := switchSessionCommand(" "); err == nil {
+		t.Fatal("expected missing session path error")
+	}
+	if _, err := forkCommand(""); err == nil {
+		t.Fatal("expected missing entry id error")
+	}
+	command, err := forkCommand(" entry-1 ")
+	if err != nil {
+		t.Fatalf("forkCommand returned error: %v", err)
+	}
+	if command["type"] != rpc.CommandFork || command["entryId"] != "entry-1" {
+		t.Fatalf("unexpected fork command: %+v", command)
+	}
+}
+
+func TestDecodeForkMessagesRequiresEntryID(t *testing.T) {
+	_, err := decodeForkMessages(json.RawMessage(`{"messages":[{"text":"hello"}]}`))
+	if !errors.Is(err, ErrProtocolViolation) {
+		t.Fatalf("expected protocol violation, got %v", err)
+	}
+	if _, err := decodeForkMessages(json.RawMessage(`null`)); !errors.Is(err, ErrProtocolViolation) {
+		t.Fatalf("expected protocol violation for null data, got %v", err)
+	}
+}
+
+func TestSessionBranchingMirrorMethods(t *testing.T) {
+	testsupport.SetupFakePI(t, "happy")
+
+	options := DefaultSessionOptions()
+	options.Auth.Anthropic.APIKey = Credential{Value: "test-key"}
+	client, err := StartSession(options)
+	if err != nil {
+		t.Fatalf("StartSession returned error: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	messages, err := client.GetForkMessages(ctx)
+	if err != nil {
+		t.Fatalf("GetForkMessages returned error: %v", err)
+	}
+	if len(messages) != 2 || messages[1].EntryID != "entry-3" || messages[1].Text != "now add tests" {
+		t.Fatalf("unexpected fork messages: %+v", messages)
+	}
+
+	forked, err := client.Fork(ctx, messages[0].EntryID)
+	if err != nil {
+		t.Fatalf("Fork returned error: %v", err)
+	}
+	if forked.Cancelled || forked.Text != "add a README" {
+		t.Fatalf("unexpected fork result: %+v", forked)
+	}
+
+	_, err = client.Fork(ctx, "entry-missing")
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Command != rpc.CommandFork {
+		t.Fatalf("expected fork RPCError, got %v", err)
+	}
+
+	cancelled, err := client.SwitchSession(ctx, "/tmp/other-session.jsonl")
+	if err != nil {
+		t.Fatalf("SwitchSession returned error: %v", err)
+	}
+	if cancelled {
+		t.Fatal("expected switch not to be cancelled")
+	}
+	state, err := client.GetState(ctx)
+	if err != nil {
+		t.Fatalf("GetState returned error: %v", err)
+	}
+	if state.SessionFile != "/tmp/other-session.jsonl" {
+		t.Fatalf("expected switched session file, got %q", state.SessionFile)
+	}
+
+	cancelled, err = client.SwitchSession(ctx, "cancel-switch")
+	if err != nil {
+		t.Fatalf("SwitchSession returned error: %v", err)
+	}
+	if !cancelled {
+		t.Fatal("expected switch to be cancelled")
+	}
+}
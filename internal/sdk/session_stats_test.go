@@ -0,0 +1,142 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/joshp123/pi-golang/internal/testsupport"
+)
+
+func TestDecodeSessionStatsRequiresTokens(t *testing.T) {
+	stats, err := decodeSessionStats(json.RawMessage(`{"sessionId":"s-1","userMessages":2,"assistantMessages":3,"toolCalls":4,"toolResults":4,"totalMessages":13,"tokens":{"input":100,"output":50,"cacheRead":10,"cacheWrite":5,"total":165},"cost":0.5}`))
+	if err != nil {
+		t.Fatalf("decodeSessionStats returned error: %v", err)
+	}
+	if stats.UserMessages != 2 || stats.AssistantMessages != 3 || stats.ToolCalls != 4 || stats.TotalMessages != 13 {
+		t.Fatalf("unexpected counts: %+v", stats)
+	}
+	if stats.Usage.Input != 100 || stats.Usage.CacheRead != 10 || stats.Usage.TotalTokens != 165 || stats.Usage.Cost == nil || stats.Usage.Cost.Total != 0.5 {
+		t.Fatalf("unexpected usage: %+v", stats.Usage)
+	}
+
+	if _, err := decodeSessionStats(json.RawMessage(`{}`)); !errors.Is(err, ErrProtocolViolation) {
+		t.Fatalf("expected protocol violation for missing tokens, got %v", err)
+	}
+	if _, err := decodeSessionStats(json.RawMessage(`null`)); !errors.Is(err, ErrProtocolViolation) {
+		t.Fatalf("expected protocol violation for null data, got %v", err)
+	}
+}
+
+func TestUsageDeltaSubtractsCumulativeStats(t *testing.T) {
+	delta := usageDelta(
+		Usage{Input: 10, Output: 5, CacheWrite: 8, TotalTokens: 15, Cost: &Cost{Total: 0.25}},
+		Usage{Input: 30, Output: 9, CacheRead: 4, TotalTokens: 43, Cost: &Cost{Total: 1}},
+	)
+	if delta.Input != 20 || delta.Output != 4 || delta.CacheRead != 4 || delta.CacheWrite != 0 || delta.TotalTokens != 28 {
+		t.Fatalf("unexpected delta: %+v", delta)
+	}
+	if delta.Cost == nil || delta.Cost.Total != 0.75 {
+		t.Fatalf("unexpected cost delta: %+v", delta.Cost)
+	}
+}
+
+func TestAddTurnUsageSumsAndClamps(t *testing.T) {
+	var total Usage
+	addTurnUsage(&total, Usage{Input: 10, Output: 5, Cost: &Cost{Total: 0.25}})
+	addTurnUsage(&total, Usage{Input: 20, Output: -3, CacheRead: 4, TotalTokens: 30, Cost: &Cost{Total: -1}})
+	if total.Input != 30 || total.Output != 5 || total.CacheRead != 4 || total.TotalTokens != 45 {
+		t.Fatalf("unexpected total: %+v", total)
+	}
+	if total.Cost == nil || total.Cost.Total != 0.25 {
+		t.Fatalf("unexpected cost total: %+v", total.Cost)
+	}
+}
+
+func TestGetSessionStatsMirror(t *testing.T) {
+	testsupport.SetupFakePI(t, "happy")
+
+	client, err := StartOneShot(testModelOneShotOptions())
+	if err != nil {
+		t.Fatalf("StartOneShot returned error: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	if _, err := client.Run(ctx, PromptRequest{Message: "hello"}); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	stats, err := client.GetSessionStats(ctx)
+	if err != nil {
+		t.Fatalf("GetSessionStats returned error: %v", err)
+	}
+	if stats.UserMessages != 1 || stats.Usage.TotalTokens != 15 || stats.Usage.Cost == nil || stats.Usage.Cost.Total != 0.25 {
+		t.Fatalf("unexpected cumulative stats: %+v", stats)
+	}
+}
+
+func TestRunDetailedReportsUsageDelta(t *testing.T) {
+	testsupport.SetupFakePI(t, "happy")
+
+	client, err := StartOneShot(testModelOneShotOptions())
+	if err != nil {
+		t.Fatalf("StartOneShot returned error: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	for run := 0; run < 2; run++ {
+		result, err := client.RunDetailed(ctx, PromptRequest{Message: "hello"})
+		if err != nil {
+			t.Fatalf("RunDetailed returned error: %v", err)
+		}
+		delta := result.UsageDelta
+		if delta == nil {
+			t.Fatal("expected usage delta")
+		}
+		if delta.Input != 10 || delta.Output != 5 || delta.TotalTokens != 15 || delta.Cost == nil || delta.Cost.Total != 0.25 {
+			t.Fatalf("unexpected delta for run %d: %+v", run, delta)
+		}
+	}
+}
+
+func TestRunDetailedFallsBackToTurnUsageWithoutStats(t *testing.T) {
+	testsupport.SetupFakePI(t, "run_tool_calls")
+
+	client, err := StartOneShot(testModelOneShotOptions())
+	if err != nil {
+		t.Fatalf("StartOneShot returned error: %v", err)
+	}
+	defer client.Close()
+
+	result, err := client.RunDetailed(context.Background(), PromptRequest{Message: "list"})
+	if err != nil {
+		t.Fatalf("RunDetailed returned error: %v", err)
+	}
+	delta := result.UsageDelta
+	if delta == nil {
+		t.Fatal("expected usage delta")
+	}
+	if delta.Input != 250 || delta.Output != 30 || delta.TotalTokens != 280 || delta.Cost != nil {
+		t.Fatalf("unexpected delta: %+v", delta)
+	}
+}
+
+func TestRunDetailedOmitsUsageDeltaWithoutStatsOrTurnUsage(t *testing.T) {
+	testsupport.SetupFakePI(t, "structured_output")
+
+	client, err := StartOneShot(testModelOneShotOptions())
+	if err != nil {
+		t.Fatalf("StartOneShot returned error: %v", err)
+	}
+	defer client.Close()
+
+	result, err := client.RunDetailed(context.Background(), PromptRequest{Message: "hello"})
+	if err != nil {
+		t.Fatalf("RunDetailed returned error: %v", err)
+	}
+	if result.UsageDelta != nil {
+		t.Fatalf("expected nil usage delta, got %+v", result.UsageDelta)
+	}
+}
@@ -3,6 +3,8 @@ package sdk
 import (
 	"encoding/json"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/joshp123/pi-golang/internal/stream"
 )
@@ -18,6 +20,42 @@ func newEventHub() *stream.Hub[Event] {
 	)
 }
 
+// SubscribeTyped is Subscribe with DecodeEvent applied to every event.
+// Backpressure still follows policy; known events that fail to decode arrive as InvalidEvent.
+func (client *Client) SubscribeTyped(policy SubscriptionPolicy) (<-chan TypedEvent, func(), error) {
+	events, cancel, err := client.Subscribe(policy)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	typedEvents := make(chan TypedEvent)
+	done := make(chan struct{})
+	var stopOnce sync.Once
+	stop := func() {
+		stopOnce.Do(func() {
+			close(done)
+			cancel()
+		})
+	}
+
+	go func() {
+		defer close(typedEvents)
+		for event := range events {
+			decoded, err := DecodeEvent(event)
+			if err != nil {
+				decoded = InvalidEvent{Type: event.Type, Raw: event.Raw, Err: err}
+			}
+			select {
+			case typedEvents <- decoded:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return typedEvents, stop, nil
+}
+
 func validateSubscriptionPolicy(policy SubscriptionPolicy) error {
 	if policy.Buffer <= 0 {
 		return fmt.Errorf("%w: buffer must be > 0", ErrInvalidSubscriptionPolicy)
@@ -66,5 +104,5 @@ func newSubscriptionDropEvent(mode SubscriptionMode, droppedType string) Event {
 		"mode":        mode,
 		"droppedType": droppedType,
 	})
-	return Event{Type: EventTypeSubscriptionDrop, Raw: raw}
+	return Event{Type: EventTypeSubscriptionDrop, Raw: raw, ReceivedAt: time.Now()}
 }
@@ -0,0 +1,133 @@
+package sdk_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/joshp123/pi-golang/internal/sdk"
+)
+
+func TestApproveToolCallDeniesAndRecordsDenials(t *testing.T) {
+	setupFakePI(t, "approval_gate")
+
+	var seen []string
+	options := testOneShotOptions()
+	options.ApproveToolCall = func(ctx context.Context, request sdk.ToolCallRequest) (sdk.Decision, error) {
+		seen = append(seen, request.ToolName)
+		if request.ToolName == "bash" {
+			return sdk.Decision{Action: sdk.DecisionDeny, Reason: "destructive command"}, nil
+		}
+		return sdk.Decision{Action: sdk.DecisionAllow}, nil
+	}
+
+	result := runApprovalScenario(t, options)
+
+	if strings.Join(seen, ",") != "bash,read" {
+		t.Fatalf("unexpected approval requests: %v", seen)
+	}
+	replies := strings.Split(result.Outcome.Text, "\n")
+	if len(replies) != 2 || replies[0] != `{"action":"deny","reason":"destructive command"}` || replies[1] != `{"action":"allow"}` {
+		t.Fatalf("unexpected decisions sent upstream: %q", result.Outcome.Text)
+	}
+	if len(result.Denials) != 1 {
+		t.Fatalf("expected one denial, got %+v", result.Denials)
+	}
+	denial := result.Denials[0]
+	if denial.ToolCallID != "call-1" || denial.ToolName != "bash" || denial.Reason != "destructive command" {
+		t.Fatalf("unexpected denial: %+v", denial)
+	}
+	if string(denial.Input) != `{"command":"rm -rf build"}` {
+		t.Fatalf("unexpected denial input: %s", denial.Input)
+	}
+}
+
+func TestApproveToolCallFailsClosedOnError(t *testing.T) {
+	setupFakePI(t, "approval_gate")
+
+	options := testOneShotOptions()
+	options.ApproveToolCall = func(context.Context, sdk.ToolCallRequest) (sdk.Decision, error) {
+		return sdk.Decision{Action: sdk.DecisionAllow}, errors.New("policy service unavailable")
+	}
+
+	result := runApprovalScenario(t, options)
+	if len(result.Denials) != 2 {
+		t.Fatalf("expected both calls denied, got %+v", result.Denials)
+	}
+	for _, denial := range result.Denials {
+		if !strings.Contains(denial.Reason, "policy service unavailable") {
+			t.Fatalf("expected callback error in denial reason, got %q", denial.Reason)
+		}
+	}
+}
+
+func TestApproveToolCallDeniesWhenCallbackOutlivesUITimeout(t *testing.T) {
+	setupFakePI(t, "approval_gate")
+
+	options := testOneShotOptions()
+	options.UITimeout = 50 * time.Millisecond
+	options.ApproveToolCall = func(ctx context.Context, request sdk.ToolCallRequest) (sdk.Decision, error) {
+		if request.ToolName == "bash" {
+			<-ctx.Done()
+		}
+		return sdk.Decision{Action: sdk.DecisionAllow}, nil
+	}
+
+	result := runApprovalScenario(t, options)
+	if len(result.Denials) != 1 || result.Denials[0].Reason != "approval interrupted: timed out" {
+		t.Fatalf("expected timed-out bash call to be denied, got %+v", result.Denials)
+	}
+}
+
+func TestApproveToolCallDeniesWhenRunIsAborted(t *testing.T) {
+	setupFakePI(t, "approval_gate")
+
+	waiting := make(chan struct{})
+	options := testOneShotOptions()
+	options.ApproveToolCall = func(ctx context.Context, request sdk.ToolCallRequest) (sdk.Decision, error) {
+		if request.ToolName == "bash" {
+			close(waiting)
+			<-ctx.Done()
+		}
+		return sdk.Decision{Action: sdk.DecisionAllow}, nil
+	}
+	client, err := sdk.StartOneShot(options)
+	if err != nil {
+		t.Fatalf("StartOneShot returned error: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	go func() {
+		<-waiting
+		_ = client.Abort(ctx)
+	}()
+	result, err := client.RunDetailed(ctx, sdk.PromptRequest{Message: "clean the build"})
+	if err != nil {
+		t.Fatalf("RunDetailed returned error: %v", err)
+	}
+	if len(result.Denials) != 1 || result.Denials[0].Reason != "approval interrupted: run aborted" {
+		t.Fatalf("expected aborted bash call to be denied, got %+v", result.Denials)
+	}
+}
+
+func runApprovalScenario(t *testing.T, options sdk.OneShotOptions) sdk.RunDetailedResult {
+	t.Helper()
+
+	client, err := sdk.StartOneShot(options)
+	if err != nil {
+		t.Fatalf("StartOneShot returned error: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	result, err := client.RunDetailed(ctx, sdk.PromptRequest{Message: "clean the build"})
+	if err != nil {
+		t.Fatalf("RunDetailed returned error: %v", err)
+	}
+	return result
+}
@@ -0,0 +1,91 @@
+package sdk_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sdk "github.com/joshp123/pi-golang/internal/sdk"
+)
+
+func TestRunDetailedWithOptionsAbortsOnBudget(t *testing.T) {
+	cases := []struct {
+		name    string
+		message string
+		budget  sdk.Budget
+		limit   sdk.BudgetLimit
+		turns   int
+	}{
+		{name: "output tokens", message: "loop", budget: sdk.Budget{MaxOutputTokens: 1000}, limit: sdk.BudgetLimitOutputTokens, turns: 1},
+		{name: "input tokens", message: "loop", budget: sdk.Budget{MaxInputTokens: 500}, limit: sdk.BudgetLimitInputTokens, turns: 1},
+		{name: "cost", message: "loop", budget: sdk.Budget{MaxCostUSD: 0.04}, limit: sdk.BudgetLimitCost, turns: 1},
+		{name: "turns", message: "loop", budget: sdk.Budget{MaxTurns: 1}, limit: sdk.BudgetLimitTurns, turns: 1},
+		{name: "tool calls", message: "loop", budget: sdk.Budget{MaxToolCalls: 1}, limit: sdk.BudgetLimitToolCalls, turns: 1},
+		{name: "wall time", message: "stall", budget: sdk.Budget{MaxWallTime: 50 * time.Millisecond}, limit: sdk.BudgetLimitWallTime, turns: 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			setupFakePI(t, "budget_run")
+
+			client, err := sdk.StartOneShot(testOneShotOptions())
+			if err != nil {
+				t.Fatalf("sdk.StartOneShot failed: %v", err)
+			}
+			defer client.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			_, err = client.RunDetailedWithOptions(ctx, sdk.PromptRequest{Message: tc.message}, sdk.RunOptions{Budget: tc.budget})
+			if !errors.Is(err, sdk.ErrBudgetExceeded) {
+				t.Fatalf("expected ErrBudgetExceeded, got %v", err)
+			}
+			var budgetErr *sdk.BudgetExceededError
+			if !errors.As(err, &budgetErr) {
+				t.Fatalf("expected *BudgetExceededError, got %T", err)
+			}
+			if budgetErr.Limit != tc.limit {
+				t.Fatalf("expected limit %s, got %s (%v)", tc.limit, budgetErr.Limit, err)
+			}
+			partial := budgetErr.Partial
+			if partial.Outcome.Status != sdk.TerminalStatusAborted || partial.Outcome.Text != "stopped" {
+				t.Fatalf("expected aborted partial outcome, got %+v", partial.Outcome)
+			}
+			if len(partial.Turns) != tc.turns {
+				t.Fatalf("expected %d completed turns in partial result, got %d", tc.turns, len(partial.Turns))
+			}
+		})
+	}
+}
+
+func TestBudgetExceededErrorReportsSpend(t *testing.T) {
+	setupFakePI(t, "budget_run")
+
+	client, err := sdk.StartOneShot(testOneShotOptions())
+	if err != nil {
+		t.Fatalf("sdk.StartOneShot failed: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err = client.RunDetailedWithOptions(ctx, sdk.PromptRequest{Message: "loop"}, sdk.RunOptions{Budget: sdk.Budget{MaxOutputTokens: 1000}})
+	var budgetErr *sdk.BudgetExceededError
+	if !errors.As(err, &budgetErr) {
+		t.Fatalf("expected *BudgetExceededError, got %v", err)
+	}
+	spent := budgetErr.Spent
+	if spent.InputTokens != 800 || spent.OutputTokens != 1100 || spent.Turns != 2 || spent.ToolCalls != 1 {
+		t.Fatalf("unexpected spend: %+v", spent)
+	}
+	if err.Error() != "run budget exceeded: output_tokens 1100 > 1000" {
+		t.Fatalf("unexpected error text %q", err.Error())
+	}
+	// Events already streamed before the abort took effect are still collected.
+	calls := budgetErr.Partial.ToolCalls
+	if len(calls) != 2 || !calls[0].Completed || calls[1].Completed {
+		t.Fatalf("expected one completed and one interrupted tool call, got %+v", calls)
+	}
+}
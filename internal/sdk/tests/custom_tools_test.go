@@ -0,0 +1,136 @@
+package sdk_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/joshp123/pi-golang/internal/sdk"
+)
+
+func TestCustomToolsRoundTripThroughExtensionBridge(t *testing.T) {
+	setupFakePI(t, "custom_tools")
+
+	var gotArgs json.RawMessage
+	options := testOneShotOptions()
+	options.Tools = []sdk.ToolDefinition{
+		{
+			Name:        "lookup_ticket",
+			Description: "Look up a ticket by id",
+			Parameters:  json.RawMessage(`{"type":"object","properties":{"id":{"type":"string"}},"required":["id"]}`),
+			Execute: func(ctx context.Context, args json.RawMessage) (sdk.ToolResult, error) {
+				gotArgs = args
+				return sdk.ToolResult{Text: "T-1: open", Details: json.RawMessage(`{"status":"open"}`)}, nil
+			},
+		},
+	}
+
+	client, err := sdk.StartOneShot(options)
+	if err != nil {
+		t.Fatalf("StartOneShot returned error: %v", err)
+	}
+	defer client.Close()
+
+	events, unsubscribe, err := client.Subscribe(sdk.SubscriptionPolicy{Buffer: 64, Mode: sdk.SubscriptionModeRing})
+	if err != nil {
+		t.Fatalf("Subscribe returned error: %v", err)
+	}
+	defer unsubscribe()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	result, err := client.Run(ctx, sdk.PromptRequest{Message: "look up T-1"})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	for event := readEventOrFail(t, events); event.Type != sdk.EventTypeAgentEnd; event = readEventOrFail(t, events) {
+		if event.Type == sdk.EventTypeExtensionUIRequest {
+			t.Fatalf("tool bridge frame leaked to subscribers: %s", event.Raw)
+		}
+	}
+
+	if string(gotArgs) != `{"id":"T-1"}` {
+		t.Fatalf("unexpected tool args: %s", gotArgs)
+	}
+	replies := strings.Split(result.Text, "\n")
+	if len(replies) != 2 {
+		t.Fatalf("expected two bridge replies, got %q", result.Text)
+	}
+	if replies[0] != `{"text":"T-1: open","details":{"status":"open"}}` {
+		t.Fatalf("unexpected tool reply: %s", replies[0])
+	}
+	if replies[1] != `{"error":"unknown tool \"broken_tool\""}` {
+		t.Fatalf("unexpected unknown-tool reply: %s", replies[1])
+	}
+}
+
+func TestCustomToolTimeoutFailsToolCall(t *testing.T) {
+	setupFakePI(t, "custom_tools")
+
+	options := testOneShotOptions()
+	options.Tools = []sdk.ToolDefinition{blockingTool(50*time.Millisecond, nil)}
+
+	result := runCustomTools(t, options)
+	if reply := strings.Split(result.Text, "\n")[0]; reply != `{"error":"tool \"lookup_ticket\" timed out"}` {
+		t.Fatalf("unexpected timed-out tool reply: %s", reply)
+	}
+}
+
+func TestCustomToolCancelledWhenPiAbortsToolCall(t *testing.T) {
+	setupFakePI(t, "custom_tools_cancel")
+
+	cancelled := make(chan error, 1)
+	options := testOneShotOptions()
+	options.Tools = []sdk.ToolDefinition{blockingTool(0, cancelled)}
+
+	result := runCustomTools(t, options)
+	if result.Text != `{"error":"tool \"lookup_ticket\" cancelled"}` {
+		t.Fatalf("unexpected cancelled tool reply: %s", result.Text)
+	}
+	select {
+	case err := <-cancelled:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected Execute ctx to be cancelled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Execute ctx was not cancelled")
+	}
+}
+
+// blockingTool waits for ctx and reports why it ended on done, when set.
+func blockingTool(timeout time.Duration, done chan<- error) sdk.ToolDefinition {
+	return sdk.ToolDefinition{
+		Name:        "lookup_ticket",
+		Description: "Look up a ticket by id",
+		Timeout:     timeout,
+		Execute: func(ctx context.Context, args json.RawMessage) (sdk.ToolResult, error) {
+			<-ctx.Done()
+			if done != nil {
+				done <- ctx.Err()
+			}
+			return sdk.ToolResult{}, ctx.Err()
+		},
+	}
+}
+
+func runCustomTools(t *testing.T, options sdk.OneShotOptions) sdk.RunResult {
+	t.Helper()
+
+	client, err := sdk.StartOneShot(options)
+	if err != nil {
+		t.Fatalf("StartOneShot returned error: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	result, err := client.Run(ctx, sdk.PromptRequest{Message: "look up T-1"})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	return result
+}
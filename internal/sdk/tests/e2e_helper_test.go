@@ -20,7 +20,7 @@ func TestHelperProcess(t *testing.T) {
 	}
 
 	scenario := testsupport.ScenarioFromArgs(os.Args, "happy")
-	if err := testsupport.RunScenario(scenario, os.Args, os.Stdin, os.Stdout); err != nil {
+	if err := testsupport.RunScenario(scenario, os.Args, os.Stdin, os.Stdout, os.Stderr); err != nil {
 		fmt.Fprintf(os.Stderr, "helper scenario failed: %v\n", err)
 		os.Exit(1)
 	}
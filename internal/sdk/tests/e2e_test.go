@@ -213,6 +213,67 @@ func TestRunDetailedCapturesCompactionAndRetrySignals(t *testing.T) {
 	if result.AutoRetryEnd == nil || !result.AutoRetryEnd.Success {
 		t.Fatalf("expected auto_retry_end success=true, got %+v", result.AutoRetryEnd)
 	}
+	if len(result.Compactions) != 1 || len(result.Retries) != 1 {
+		t.Fatalf("expected one compaction and one retry episode, got %+v / %+v", result.Compactions, result.Retries)
+	}
+}
+
+func TestRunDetailedKeepsEveryRecoveryEpisode(t *testing.T) {
+	setupFakePI(t, "run_recovery_timeline")
+
+	client, err := sdk.StartOneShot(testOneShotOptions())
+	if err != nil {
+		t.Fatalf("sdk.StartOneShot failed: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	result, err := client.RunDetailed(ctx, sdk.PromptRequest{Message: "start"})
+	if err != nil {
+		t.Fatalf("RunDetailed failed: %v", err)
+	}
+
+	if len(result.Compactions) != 2 {
+		t.Fatalf("expected 2 compaction episodes, got %+v", result.Compactions)
+	}
+	first, second := result.Compactions[0], result.Compactions[1]
+	if first.Start.Reason != "overflow" || first.End == nil || !first.End.WillRetry {
+		t.Fatalf("unexpected first compaction: %+v", first)
+	}
+	if second.Start.Reason != "threshold" || second.End == nil || second.End.WillRetry {
+		t.Fatalf("unexpected second compaction: %+v", second)
+	}
+	if result.AutoCompactionStart == nil || result.AutoCompactionStart.Reason != "threshold" {
+		t.Fatalf("expected last-seen compaction pointer to track the final episode, got %+v", result.AutoCompactionStart)
+	}
+
+	if len(result.Retries) != 2 {
+		t.Fatalf("expected 2 retry attempts, got %+v", result.Retries)
+	}
+	if result.Retries[0].Start.Attempt != 1 || result.Retries[0].End != nil {
+		t.Fatalf("expected superseded first attempt, got %+v", result.Retries[0])
+	}
+	if result.Retries[1].Start.Attempt != 2 || result.Retries[1].End == nil || !result.Retries[1].End.Success {
+		t.Fatalf("expected successful second attempt, got %+v", result.Retries[1])
+	}
+
+	offsets := []time.Duration{first.StartedAt, first.EndedAt, result.Retries[0].StartedAt, result.Retries[1].EndedAt, second.StartedAt, second.EndedAt}
+	for index := 1; index < len(offsets); index++ {
+		if offsets[index] < offsets[index-1] || offsets[index] <= 0 {
+			t.Fatalf("expected increasing positive offsets from run start, got %v", offsets)
+		}
+	}
+
+	summary := sdk.ClassifyManaged(result)
+	if summary.Class != sdk.CompletionClassOKAfterRecovery {
+		t.Fatalf("expected ok_after_recovery, got %q", summary.Class)
+	}
+	want := sdk.RecoveryFacts{CompactionObserved: true, OverflowDetected: true, Recovered: true, Compactions: 2, OverflowCompactions: 1, RetryAttempts: 2, RetriesRecovered: 1}
+	if summary.Facts != want {
+		t.Fatalf("unexpected facts: got=%+v want=%+v", summary.Facts, want)
+	}
 }
 
 func TestSendStillReturnsResponseWhenBlockSubscriberIsNotConsuming(t *testing.T) {
@@ -276,3 +337,77 @@ func testOneShotOptions() sdk.OneShotOptions {
 	opts.Auth.Anthropic.APIKey = sdk.Credential{Value: "test-key"}
 	return opts
 }
+
+func TestRunDetailedRecordsToolCallTimeline(t *testing.T) {
+	setupFakePI(t, "run_tool_calls")
+
+	client, err := sdk.StartOneShot(testOneShotOptions())
+	if err != nil {
+		t.Fatalf("sdk.StartOneShot failed: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	result, err := client.RunDetailed(ctx, sdk.PromptRequest{Message: "list files"})
+	if err != nil {
+		t.Fatalf("RunDetailed failed: %v", err)
+	}
+	if len(result.ToolCalls) != 2 {
+		t.Fatalf("expected 2 tool calls, got %d (%+v)", len(result.ToolCalls), result.ToolCalls)
+	}
+
+	bash := result.ToolCalls[0]
+	if bash.ToolCallID != "call-1" || bash.ToolName != "bash" || string(bash.Args) != `{"command":"ls"}` {
+		t.Fatalf("unexpected first tool call: %+v", bash)
+	}
+	if !bash.Completed || bash.IsError || len(bash.PartialResult) == 0 || len(bash.Result) == 0 {
+		t.Fatalf("expected completed bash call with partial + final result, got %+v", bash)
+	}
+	if bash.Duration <= 0 {
+		t.Fatalf("expected positive duration, got %s", bash.Duration)
+	}
+
+	read := result.ToolCalls[1]
+	if read.ToolName != "read" || !read.IsError || !read.Completed {
+		t.Fatalf("expected errored read call, got %+v", read)
+	}
+}
+
+func TestRunDetailedRecordsTurnSummaries(t *testing.T) {
+	setupFakePI(t, "run_tool_calls")
+
+	client, err := sdk.StartOneShot(testOneShotOptions())
+	if err != nil {
+		t.Fatalf("sdk.StartOneShot failed: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	result, err := client.RunDetailed(ctx, sdk.PromptRequest{Message: "list files"})
+	if err != nil {
+		t.Fatalf("RunDetailed failed: %v", err)
+	}
+	if len(result.Turns) != 2 {
+		t.Fatalf("expected 2 turns, got %d", len(result.Turns))
+	}
+
+	toolTurn := result.Turns[0]
+	if toolTurn.Index != 0 || toolTurn.Message.StopReason != "toolUse" {
+		t.Fatalf("unexpected first turn: %+v", toolTurn)
+	}
+	if len(toolTurn.ToolResults) != 2 || toolTurn.ToolResults[1].ToolCallID != "call-2" || !toolTurn.ToolResults[1].IsError {
+		t.Fatalf("unexpected first turn tool results: %+v", toolTurn.ToolResults)
+	}
+	if toolTurn.Usage == nil || toolTurn.Usage.Input != 100 {
+		t.Fatalf("expected first turn usage input=100, got %+v", toolTurn.Usage)
+	}
+
+	finalTurn := result.Turns[1]
+	if finalTurn.Index != 1 || len(finalTurn.ToolResults) != 0 {
+		t.Fatalf("unexpected final turn: %+v", finalTurn)
+	}
+}
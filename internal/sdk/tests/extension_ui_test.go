@@ -0,0 +1,234 @@
+package sdk_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	sdk "github.com/joshp123/pi-golang/internal/sdk"
+)
+
+type scriptedUIHandler struct {
+	mu       sync.Mutex
+	requests []sdk.ExtensionUIRequest
+
+	selectValue string
+	confirm     bool
+	inputValue  string
+	editorValue string
+	err         error
+	block       bool
+}
+
+func (handler *scriptedUIHandler) record(request sdk.ExtensionUIRequest) {
+	handler.mu.Lock()
+	defer handler.mu.Unlock()
+	handler.requests = append(handler.requests, request)
+}
+
+func (handler *scriptedUIHandler) seen() []sdk.ExtensionUIRequest {
+	handler.mu.Lock()
+	defer handler.mu.Unlock()
+	return append([]sdk.ExtensionUIRequest(nil), handler.requests...)
+}
+
+func (handler *scriptedUIHandler) wait(ctx context.Context) error {
+	if handler.block {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return handler.err
+}
+
+func (handler *scriptedUIHandler) Select(ctx context.Context, request sdk.ExtensionUIRequest) (string, error) {
+	handler.record(request)
+	return handler.selectValue, handler.wait(ctx)
+}
+
+func (handler *scriptedUIHandler) Confirm(ctx context.Context, request sdk.ExtensionUIRequest) (bool, error) {
+	handler.record(request)
+	return handler.confirm, handler.wait(ctx)
+}
+
+func (handler *scriptedUIHandler) Input(ctx context.Context, request sdk.ExtensionUIRequest) (string, error) {
+	handler.record(request)
+	return handler.inputValue, handler.wait(ctx)
+}
+
+func (handler *scriptedUIHandler) Editor(ctx context.Context, request sdk.ExtensionUIRequest) (string, error) {
+	handler.record(request)
+	return handler.editorValue, handler.wait(ctx)
+}
+
+func (handler *scriptedUIHandler) Notify(_ context.Context, request sdk.ExtensionUIRequest) {
+	handler.record(request)
+}
+
+func (handler *scriptedUIHandler) SetStatus(_ context.Context, request sdk.ExtensionUIRequest) {
+	handler.record(request)
+}
+
+func TestUIHandlerAnswersDialogs(t *testing.T) {
+	tests := []struct {
+		scenario string
+		method   sdk.UIMethod
+		want     string
+	}{
+		{scenario: "ui_select", method: sdk.UIMethodSelect, want: "value:beta"},
+		{scenario: "ui_confirm", method: sdk.UIMethodConfirm, want: "confirmed:true"},
+		{scenario: "ui_input", method: sdk.UIMethodInput, want: "value:Ada"},
+		{scenario: "ui_editor", method: sdk.UIMethodEditor, want: "value:draft v2"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			setupFakePI(t, test.scenario)
+
+			handler := &scriptedUIHandler{selectValue: "beta", confirm: true, inputValue: "Ada", editorValue: "draft v2"}
+			options := testOneShotOptions()
+			options.UIHandler = handler
+			result := runUIScenario(t, options)
+
+			if result.Text != test.want {
+				t.Fatalf("expected %q, got %q", test.want, result.Text)
+			}
+			requests := handler.seen()
+			if len(requests) != 1 || requests[0].Method != test.method || requests[0].ID != "ui-1" {
+				t.Fatalf("unexpected handler requests: %+v", requests)
+			}
+		})
+	}
+}
+
+func TestUIDialogsGetDefaultAnswerWithoutHandler(t *testing.T) {
+	tests := []struct {
+		scenario string
+		want     string
+	}{
+		{scenario: "ui_select", want: "cancelled"},
+		{scenario: "ui_confirm", want: "confirmed:false"},
+		{scenario: "ui_editor", want: "cancelled"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			setupFakePI(t, test.scenario)
+			result := runUIScenario(t, testOneShotOptions())
+			if result.Text != test.want {
+				t.Fatalf("expected %q, got %q", test.want, result.Text)
+			}
+		})
+	}
+}
+
+func TestUIHandlerErrorSendsDefaultAnswer(t *testing.T) {
+	setupFakePI(t, "ui_input")
+
+	options := testOneShotOptions()
+	options.UIHandler = &scriptedUIHandler{inputValue: "ignored", err: errors.New("user closed dialog")}
+	result := runUIScenario(t, options)
+	if result.Text != "cancelled" {
+		t.Fatalf("expected cancelled, got %q", result.Text)
+	}
+}
+
+func TestUIHandlerTimeoutSendsDefaultAnswer(t *testing.T) {
+	setupFakePI(t, "ui_confirm")
+
+	options := testOneShotOptions()
+	options.UIHandler = &scriptedUIHandler{confirm: true, block: true}
+	options.UITimeout = 50 * time.Millisecond
+	result := runUIScenario(t, options)
+	if result.Text != "confirmed:false" {
+		t.Fatalf("expected confirmed:false after timeout, got %q", result.Text)
+	}
+}
+
+func TestUIHandlerReceivesNotifyAndStatus(t *testing.T) {
+	setupFakePI(t, "ui_notify")
+
+	handler := &scriptedUIHandler{}
+	options := testOneShotOptions()
+	options.UIHandler = handler
+	result := runUIScenario(t, options)
+	if result.Text != "notified" {
+		t.Fatalf("unexpected result text: %q", result.Text)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(handler.seen()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	requests := handler.seen()
+	if len(requests) != 2 {
+		t.Fatalf("expected notify + setStatus, got %+v", requests)
+	}
+	for _, request := range requests {
+		switch request.Method {
+		case sdk.UIMethodNotify:
+			if request.Message != "indexing" || request.NotifyType != "info" {
+				t.Fatalf("unexpected notify request: %+v", request)
+			}
+		case sdk.UIMethodSetStatus:
+			if request.StatusKey != "indexer" || request.StatusText != "3 files" {
+				t.Fatalf("unexpected setStatus request: %+v", request)
+			}
+		default:
+			t.Fatalf("unexpected method: %+v", request)
+		}
+	}
+}
+
+func TestUIHandlerReceivesStatusUpdatesInOrder(t *testing.T) {
+	setupFakePI(t, "ui_status_burst")
+
+	handler := &scriptedUIHandler{}
+	options := testOneShotOptions()
+	options.UIHandler = handler
+	if result := runUIScenario(t, options); result.Text != "notified" {
+		t.Fatalf("unexpected result text: %q", result.Text)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(handler.seen()) < 20 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	requests := handler.seen()
+	if len(requests) != 20 {
+		t.Fatalf("expected 20 status updates, got %d", len(requests))
+	}
+	for index, request := range requests {
+		if want := fmt.Sprintf("%d files", index+1); request.StatusText != want {
+			t.Fatalf("status %d = %q, want %q (delivered out of order)", index, request.StatusText, want)
+		}
+	}
+}
+
+func TestUIOptionsRejectNegativeTimeout(t *testing.T) {
+	options := testOneShotOptions()
+	options.UITimeout = -time.Second
+	if _, err := sdk.StartOneShot(options); err == nil {
+		t.Fatal("expected negative UITimeout to be rejected")
+	}
+}
+
+func runUIScenario(t *testing.T, options sdk.OneShotOptions) sdk.RunResult {
+	t.Helper()
+
+	client, err := sdk.StartOneShot(options)
+	if err != nil {
+		t.Fatalf("StartOneShot returned error: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	result, err := client.Run(ctx, sdk.PromptRequest{Message: "ask"})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	return result
+}
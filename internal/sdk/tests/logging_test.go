@@ -0,0 +1,124 @@
+package sdk_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/joshp123/pi-golang/internal/sdk"
+)
+
+func TestClientLoggerRecordsRequestsAndLifecycle(t *testing.T) {
+	setupFakePI(t, "happy")
+
+	var output syncBuffer
+	opts := testOneShotOptions()
+	opts.Logger = slog.New(slog.NewJSONHandler(&output, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client, err := sdk.StartOneShot(opts)
+	if err != nil {
+		t.Fatalf("sdk.StartOneShot failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := client.Run(ctx, sdk.PromptRequest{Message: "hello"}); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	_ = client.Close()
+
+	records := decodeLogRecords(t, output.String())
+	started := findLogRecord(t, records, "pi process started", nil)
+	if started["pid"] == nil || started["component"] != "pi-golang" {
+		t.Fatalf("expected pid-scoped client logger, got %v", started)
+	}
+	startup := findLogRecord(t, records, "starting pi process", nil)
+	if args, _ := startup["args"].([]any); len(args) == 0 {
+		t.Fatalf("expected startup args, got %v", startup)
+	}
+	request := findLogRecord(t, records, "rpc request", map[string]any{"command": "prompt"})
+	response := findLogRecord(t, records, "rpc response", map[string]any{"command": "prompt", "request_id": request["request_id"]})
+	if response["success"] != true || response["level"] != "DEBUG" || response["latency"] == nil {
+		t.Fatalf("unexpected rpc response record: %v", response)
+	}
+	findLogRecord(t, records, "dispatch event", map[string]any{"event_type": sdk.EventTypeAgentEnd})
+	findLogRecord(t, records, "pi process stopped", nil)
+}
+
+func TestClientLoggerRecordsSubscriptionDrops(t *testing.T) {
+	setupFakePI(t, "happy")
+
+	var output syncBuffer
+	opts := testOneShotOptions()
+	opts.Logger = slog.New(slog.NewJSONHandler(&output, nil))
+	client, err := sdk.StartOneShot(opts)
+	if err != nil {
+		t.Fatalf("sdk.StartOneShot failed: %v", err)
+	}
+	defer client.Close()
+
+	// Never read: every event past the buffer is dropped.
+	_, cancelEvents, err := client.Subscribe(sdk.SubscriptionPolicy{Buffer: 1, Mode: sdk.SubscriptionModeDrop})
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	defer cancelEvents()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for range 3 {
+		if _, err := client.Run(ctx, sdk.PromptRequest{Message: "hello"}); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(output.String(), "subscription dropped event") {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected a subscription drop record, got %s", output.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	for _, record := range decodeLogRecords(t, output.String()) {
+		if record["msg"] == "subscription dropped event" && (record["level"] != "WARN" || record["mode"] != string(sdk.SubscriptionModeDrop)) {
+			t.Fatalf("unexpected drop record: %v", record)
+		}
+	}
+}
+
+func decodeLogRecords(t *testing.T, output string) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		var record map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("invalid log line %q: %v", scanner.Text(), err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func findLogRecord(t *testing.T, records []map[string]any, message string, attrs map[string]any) map[string]any {
+	t.Helper()
+	for _, record := range records {
+		if record["msg"] != message {
+			continue
+		}
+		matched := true
+		for key, value := range attrs {
+			if record[key] != value {
+				matched = false
+			}
+		}
+		if matched {
+			return record
+		}
+	}
+	t.Fatalf("no %q record with %v", message, attrs)
+	return nil
+}
@@ -0,0 +1,173 @@
+package sdk_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	sdk "github.com/joshp123/pi-golang/internal/sdk"
+)
+
+func TestPoolRunsConcurrentlyAndReplacesUsedClients(t *testing.T) {
+	setupFakePI(t, "happy")
+
+	pool, err := sdk.NewPool(testOneShotOptions(), sdk.PoolOptions{Size: 2})
+	if err != nil {
+		t.Fatalf("sdk.NewPool failed: %v", err)
+	}
+	defer pool.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 4)
+	for range 4 {
+		wg.Go(func() {
+			result, err := pool.Run(ctx, sdk.PromptRequest{Message: "hello"})
+			if err == nil && result.Text != "hello from helper" {
+				err = errors.New("unexpected text " + result.Text)
+			}
+			errs <- err
+		})
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("pool run failed: %v", err)
+		}
+	}
+
+	stats := waitForPoolIdle(t, pool, 2)
+	if stats.Runs != 4 || stats.Started != 6 || stats.Replaced != 0 || stats.InUse != 0 {
+		t.Fatalf("unexpected pool stats: %+v", stats)
+	}
+}
+
+func TestPoolReuseResetsClientInsteadOfRestarting(t *testing.T) {
+	setupFakePI(t, "happy")
+
+	pool, err := sdk.NewPool(testOneShotOptions(), sdk.PoolOptions{Size: 1, Reuse: true})
+	if err != nil {
+		t.Fatalf("sdk.NewPool failed: %v", err)
+	}
+	defer pool.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for range 3 {
+		if _, err := pool.Run(ctx, sdk.PromptRequest{Message: "hello"}); err != nil {
+			t.Fatalf("pool run failed: %v", err)
+		}
+	}
+	if stats := pool.Stats(); stats.Runs != 3 || stats.Started != 1 || stats.Idle != 1 {
+		t.Fatalf("expected one reused client, got %+v", stats)
+	}
+}
+
+func TestPoolWithKeepsRunJSONRepairsOnOneClient(t *testing.T) {
+	setupFakePI(t, "structured_output")
+
+	pool, err := sdk.NewPool(testOneShotOptions(), sdk.PoolOptions{Size: 1, Reuse: true})
+	if err != nil {
+		t.Fatalf("sdk.NewPool failed: %v", err)
+	}
+	defer pool.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var result sdk.JSONResult[repoSummary]
+	err = pool.With(ctx, func(client *sdk.OneShotClient) error {
+		var runErr error
+		result, runErr = sdk.RunJSON[repoSummary](ctx, client, sdk.PromptRequest{Message: "summarize the repo"}, sdk.JSONOptions{})
+		return runErr
+	})
+	if err != nil {
+		t.Fatalf("pool.With failed: %v", err)
+	}
+	if result.Value != (repoSummary{Name: "pi", Stars: 5}) || len(result.Attempts) != 2 {
+		t.Fatalf("unexpected RunJSON result: %+v", result)
+	}
+	if stats := pool.Stats(); stats.Runs != 1 || stats.Started != 1 || stats.InUse != 0 {
+		t.Fatalf("expected initial prompt and repair on one borrow, got %+v", stats)
+	}
+}
+
+func TestPoolReplacesDeadClient(t *testing.T) {
+	setupFakePI(t, "die_on_prompt")
+
+	pool, err := sdk.NewPool(testOneShotOptions(), sdk.PoolOptions{Size: 1, Reuse: true})
+	if err != nil {
+		t.Fatalf("sdk.NewPool failed: %v", err)
+	}
+	defer pool.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := pool.Run(ctx, sdk.PromptRequest{Message: "boom"}); !errors.Is(err, sdk.ErrProcessDied) {
+		t.Fatalf("expected sdk.ErrProcessDied, got %v", err)
+	}
+
+	stats := waitForPoolIdle(t, pool, 1)
+	if stats.Replaced != 1 || stats.Started != 2 {
+		t.Fatalf("expected dead client to be replaced, got %+v", stats)
+	}
+}
+
+func TestPoolCloseFailsWaitingRuns(t *testing.T) {
+	setupFakePI(t, "never_respond")
+
+	pool, err := sdk.NewPool(testOneShotOptions(), sdk.PoolOptions{Size: 1})
+	if err != nil {
+		t.Fatalf("sdk.NewPool failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	errs := make(chan error, 2)
+	for range 2 {
+		go func() {
+			_, err := pool.Run(ctx, sdk.PromptRequest{Message: "stuck"})
+			errs <- err
+		}()
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for pool.Stats().InUse != 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected one borrowed client, got %+v", pool.Stats())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if err := pool.Close(); err != nil {
+		t.Fatalf("pool.Close failed: %v", err)
+	}
+	// The waiter fails immediately; the borrowed run ends when its ctx expires.
+	if err := <-errs; !errors.Is(err, sdk.ErrClientClosed) {
+		t.Fatalf("expected sdk.ErrClientClosed for waiting run, got %v", err)
+	}
+	cancel()
+	<-errs
+	if _, err := pool.Run(context.Background(), sdk.PromptRequest{Message: "late"}); !errors.Is(err, sdk.ErrClientClosed) {
+		t.Fatalf("expected sdk.ErrClientClosed after Close, got %v", err)
+	}
+}
+
+func waitForPoolIdle(t *testing.T, pool *sdk.Pool, idle int) sdk.PoolStats {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		stats := pool.Stats()
+		if stats.Idle == idle && stats.Starting == 0 {
+			return stats
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d idle clients, got %+v", idle, stats)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
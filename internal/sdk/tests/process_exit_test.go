@@ -0,0 +1,96 @@
+package sdk_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sdk "github.com/joshp123/pi-golang/internal/sdk"
+)
+
+func TestProcessExitErrorReportsExitDiagnostics(t *testing.T) {
+	setupFakePI(t, "exit_on_prompt")
+
+	client, err := sdk.StartOneShot(testOneShotOptions())
+	if err != nil {
+		t.Fatalf("sdk.StartOneShot failed: %v", err)
+	}
+	defer client.Close()
+
+	events, cancelEvents, err := client.Subscribe(sdk.SubscriptionPolicy{Buffer: 16, Mode: sdk.SubscriptionModeBlock})
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	defer cancelEvents()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err = client.Run(ctx, sdk.PromptRequest{Message: "hello"})
+	if !errors.Is(err, sdk.ErrProcessDied) {
+		t.Fatalf("expected ErrProcessDied, got %v", err)
+	}
+	var exitErr *sdk.ProcessExitError
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("expected *ProcessExitError, got %T", err)
+	}
+	if exitErr.ExitCode != 1 || exitErr.Signal != "" || exitErr.SDKInitiated {
+		t.Fatalf("unexpected exit status: %+v", exitErr)
+	}
+	if exitErr.InFlightRequestID == "" || exitErr.Uptime <= 0 {
+		t.Fatalf("expected in-flight prompt and uptime, got %+v", exitErr)
+	}
+	if len(exitErr.StderrTail) == 0 || exitErr.StderrTail[len(exitErr.StderrTail)-1] != "helper scenario failed: fatal: model runtime crashed" {
+		t.Fatalf("unexpected stderr tail %q", exitErr.StderrTail)
+	}
+
+	typed, err := sdk.DecodeEvent(waitForEvent(t, events, sdk.EventTypeProcessDied))
+	if err != nil {
+		t.Fatalf("DecodeEvent failed: %v", err)
+	}
+	died, ok := typed.(sdk.ProcessDiedEvent)
+	if !ok || died.ExitCode == nil || *died.ExitCode != 1 || died.InFlightRequestID != exitErr.InFlightRequestID || len(died.StderrTail) == 0 {
+		t.Fatalf("unexpected process_died event: %#v", typed)
+	}
+	if died.Error != exitErr.Error() {
+		t.Fatalf("expected event error %q, got %q", exitErr.Error(), died.Error)
+	}
+}
+
+func TestProcessExitErrorMarksSDKInitiatedKill(t *testing.T) {
+	setupFakePI(t, "run_hang")
+
+	client, err := sdk.StartOneShot(testOneShotOptions())
+	if err != nil {
+		t.Fatalf("sdk.StartOneShot failed: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err = client.RunDetailedWithOptions(ctx, sdk.PromptRequest{Message: "hello"}, sdk.RunOptions{
+		IdleTimeout: 50 * time.Millisecond,
+		AbortGrace:  50 * time.Millisecond,
+	})
+	if !errors.Is(err, sdk.ErrRunStalled) {
+		t.Fatalf("expected ErrRunStalled, got %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		_, err := client.GetState(ctx)
+		var exitErr *sdk.ProcessExitError
+		if errors.As(err, &exitErr) {
+			if !exitErr.SDKInitiated || exitErr.Signal != "killed" || exitErr.ExitCode != -1 {
+				t.Fatalf("unexpected exit details for sdk kill: %+v", exitErr)
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected *ProcessExitError after the process was killed, got %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,138 @@
+package sdk_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sdk "github.com/joshp123/pi-golang/internal/sdk"
+)
+
+func TestRunQueueSerialisesConcurrentRuns(t *testing.T) {
+	setupFakePI(t, "slow_run")
+
+	options := testOneShotOptions()
+	options.RunQueue = true
+	client, err := sdk.StartOneShot(options)
+	if err != nil {
+		t.Fatalf("sdk.StartOneShot failed: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	type outcome struct {
+		result sdk.RunDetailedResult
+		err    error
+	}
+	results := make(chan outcome, 2)
+	run := func() {
+		result, err := client.RunDetailed(ctx, sdk.PromptRequest{Message: "work"})
+		results <- outcome{result: result, err: err}
+	}
+	go run()
+	waitForQueue(t, client, true, 0)
+	go run()
+	waitForQueue(t, client, true, 1)
+
+	var waits []time.Duration
+	for range 2 {
+		got := <-results
+		if got.err != nil {
+			t.Fatalf("queued RunDetailed failed: %v", got.err)
+		}
+		if got.result.Outcome.Text != "done" {
+			t.Fatalf("unexpected outcome: %+v", got.result.Outcome)
+		}
+		waits = append(waits, got.result.QueueWait)
+	}
+	if waits[0] > 50*time.Millisecond || waits[1] < 100*time.Millisecond {
+		t.Fatalf("expected second run to wait for the first, got waits %v", waits)
+	}
+	if stats := client.RunQueueStats(); !stats.Enabled || stats.Running || stats.Waiting != 0 {
+		t.Fatalf("unexpected idle queue stats: %+v", stats)
+	}
+}
+
+func TestRunQueueCancellationWhileQueued(t *testing.T) {
+	setupFakePI(t, "slow_run")
+
+	options := testOneShotOptions()
+	options.RunQueue = true
+	client, err := sdk.StartOneShot(options)
+	if err != nil {
+		t.Fatalf("sdk.StartOneShot failed: %v", err)
+	}
+	defer client.Close()
+
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := client.Run(context.Background(), sdk.PromptRequest{Message: "first"})
+		firstErr <- err
+	}()
+	waitForQueue(t, client, true, 0)
+
+	queuedCtx, cancelQueued := context.WithCancel(context.Background())
+	queuedErr := make(chan error, 1)
+	go func() {
+		_, err := client.Run(queuedCtx, sdk.PromptRequest{Message: "second"})
+		queuedErr <- err
+	}()
+	waitForQueue(t, client, true, 1)
+	cancelQueued()
+
+	if err := <-queuedErr; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled for queued run, got %v", err)
+	}
+	if err := <-firstErr; err != nil {
+		t.Fatalf("first run failed: %v", err)
+	}
+}
+
+func TestRunQueueCloseWakesQueuedRuns(t *testing.T) {
+	setupFakePI(t, "never_respond")
+
+	options := testOneShotOptions()
+	options.RunQueue = true
+	client, err := sdk.StartOneShot(options)
+	if err != nil {
+		t.Fatalf("sdk.StartOneShot failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	errs := make(chan error, 2)
+	for range 2 {
+		go func() {
+			_, err := client.Run(ctx, sdk.PromptRequest{Message: "stuck"})
+			errs <- err
+		}()
+	}
+	waitForQueue(t, client, true, 1)
+
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	for range 2 {
+		if err := <-errs; !errors.Is(err, sdk.ErrClientClosed) {
+			t.Fatalf("expected sdk.ErrClientClosed, got %v", err)
+		}
+	}
+}
+
+func waitForQueue(t *testing.T, client *sdk.OneShotClient, running bool, waiting int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		stats := client.RunQueueStats()
+		if stats.Running == running && stats.Waiting == waiting {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected running=%v waiting=%d, got %+v", running, waiting, stats)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
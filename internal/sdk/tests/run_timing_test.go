@@ -0,0 +1,64 @@
+package sdk_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sdk "github.com/joshp123/pi-golang/internal/sdk"
+)
+
+func TestRunDetailedRecordsTiming(t *testing.T) {
+	setupFakePI(t, "run_timing")
+
+	client, err := sdk.StartOneShot(testOneShotOptions())
+	if err != nil {
+		t.Fatalf("sdk.StartOneShot failed: %v", err)
+	}
+	defer client.Close()
+
+	events, cancelEvents, err := client.Subscribe(sdk.SubscriptionPolicy{Buffer: 64, Mode: sdk.SubscriptionModeBlock})
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	defer cancelEvents()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := client.RunDetailed(ctx, sdk.PromptRequest{Message: "time me"})
+	if err != nil {
+		t.Fatalf("RunDetailed failed: %v", err)
+	}
+	timing := result.Timing
+
+	const step = 20 * time.Millisecond
+	if timing.PromptAck <= 0 || timing.PromptAck >= timing.FirstTextDelta {
+		t.Fatalf("expected prompt ack before first delta: %+v", timing)
+	}
+	// Lower bounds from run start hold however late the reader stamps an event;
+	// gaps between two stamps do not, so milestones are only checked for order.
+	if timing.FirstTextDelta < 2*step || timing.FirstToolCall <= timing.FirstTextDelta || timing.Total < 6*step {
+		t.Fatalf("unexpected milestone spacing: %+v", timing)
+	}
+	if timing.RetryDelay != 30*time.Millisecond || timing.Compaction != 0 {
+		t.Fatalf("unexpected retry/compaction time: %+v", timing)
+	}
+	// 40 output tokens over the final message's ~20ms stream; the measured gap
+	// can shrink when the reader is descheduled, so allow twice the nominal rate.
+	if timing.OutputTokensPerSecond <= 0 || timing.OutputTokensPerSecond > 40/(step/2).Seconds() {
+		t.Fatalf("unexpected tokens/sec: %v", timing.OutputTokensPerSecond)
+	}
+
+	var previous time.Time
+	for {
+		event := <-events
+		if event.ReceivedAt.IsZero() || event.ReceivedAt.Before(previous) {
+			t.Fatalf("expected monotonic receive stamps, got %v after %v (%s)", event.ReceivedAt, previous, event.Type)
+		}
+		previous = event.ReceivedAt
+		if event.Type == sdk.EventTypeAgentEnd {
+			return
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package sdk_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sdk "github.com/joshp123/pi-golang/internal/sdk"
+)
+
+func TestRunIdleTimeoutAbortsStalledRun(t *testing.T) {
+	setupFakePI(t, "budget_run")
+
+	client, err := sdk.StartOneShot(testOneShotOptions())
+	if err != nil {
+		t.Fatalf("sdk.StartOneShot failed: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err = client.RunDetailedWithOptions(ctx, sdk.PromptRequest{Message: "stall"}, sdk.RunOptions{IdleTimeout: 50 * time.Millisecond})
+	var stalled *sdk.RunStalledError
+	if !errors.As(err, &stalled) || !errors.Is(err, sdk.ErrRunStalled) {
+		t.Fatalf("expected *RunStalledError, got %v", err)
+	}
+	if stalled.LastEventType != sdk.EventTypeAgentStart || stalled.Killed {
+		t.Fatalf("unexpected stall details: %+v", stalled)
+	}
+	if stalled.Partial.Outcome.Status != sdk.TerminalStatusAborted {
+		t.Fatalf("expected aborted partial outcome, got %+v", stalled.Partial.Outcome)
+	}
+
+	if _, err := client.GetState(ctx); errors.Is(err, sdk.ErrProcessDied) {
+		t.Fatal("process should survive an abort that was honoured")
+	}
+}
+
+func TestRunIdleTimeoutKillsProcessIgnoringAbort(t *testing.T) {
+	setupFakePI(t, "run_hang")
+
+	client, err := sdk.StartOneShot(testOneShotOptions())
+	if err != nil {
+		t.Fatalf("sdk.StartOneShot failed: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err = client.RunDetailedWithOptions(ctx, sdk.PromptRequest{Message: "hello"}, sdk.RunOptions{
+		IdleTimeout: 50 * time.Millisecond,
+		AbortGrace:  50 * time.Millisecond,
+	})
+	var stalled *sdk.RunStalledError
+	if !errors.As(err, &stalled) {
+		t.Fatalf("expected *RunStalledError, got %v", err)
+	}
+	if !stalled.Killed || stalled.LastEventType != sdk.EventTypeAgentStart {
+		t.Fatalf("expected killed stall after agent_start, got %+v", stalled)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, err := client.GetState(ctx); errors.Is(err, sdk.ErrProcessDied) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected ErrProcessDied after the process was killed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRunIdleTimeoutDoesNotFireWhileEventsFlow(t *testing.T) {
+	setupFakePI(t, "happy")
+
+	client, err := sdk.StartOneShot(testOneShotOptions())
+	if err != nil {
+		t.Fatalf("sdk.StartOneShot failed: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := client.RunDetailedWithOptions(ctx, sdk.PromptRequest{Message: "hello"}, sdk.RunOptions{IdleTimeout: time.Second})
+	if err != nil {
+		t.Fatalf("RunDetailedWithOptions failed: %v", err)
+	}
+	if result.Outcome.Status != sdk.TerminalStatusCompleted {
+		t.Fatalf("unexpected outcome: %+v", result.Outcome)
+	}
+}
+
+func TestRunIdleTimeoutWaitsForOutstandingApproval(t *testing.T) {
+	setupFakePI(t, "approval_gate")
+
+	options := testOneShotOptions()
+	options.ApproveToolCall = func(ctx context.Context, request sdk.ToolCallRequest) (sdk.Decision, error) {
+		// pi emits nothing while it waits for the decision.
+		time.Sleep(200 * time.Millisecond)
+		return sdk.Decision{Action: sdk.DecisionAllow}, nil
+	}
+	client, err := sdk.StartOneShot(options)
+	if err != nil {
+		t.Fatalf("sdk.StartOneShot failed: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := client.RunDetailedWithOptions(ctx, sdk.PromptRequest{Message: "clean up"}, sdk.RunOptions{
+		IdleTimeout: 50 * time.Millisecond,
+		AbortGrace:  50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("expected run to survive slow approvals, got %v", err)
+	}
+	if result.Outcome.Status != sdk.TerminalStatusCompleted || len(result.Denials) != 0 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if _, err := client.GetState(ctx); errors.Is(err, sdk.ErrProcessDied) {
+		t.Fatal("process should survive slow approvals")
+	}
+}
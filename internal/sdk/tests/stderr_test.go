@@ -0,0 +1,164 @@
+package sdk_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	sdk "github.com/joshp123/pi-golang/internal/sdk"
+)
+
+func TestStderrRingKeepsTailWithinLimit(t *testing.T) {
+	setupFakePI(t, "stderr_chatter")
+
+	opts := testOneShotOptions()
+	opts.StderrLimit = 64
+	client, err := sdk.StartOneShot(opts)
+	if err != nil {
+		t.Fatalf("sdk.StartOneShot failed: %v", err)
+	}
+	defer client.Close()
+
+	lines, cancelLines, err := client.SubscribeStderr(sdk.SubscriptionPolicy{Buffer: 64, Mode: sdk.SubscriptionModeBlock})
+	if err != nil {
+		t.Fatalf("SubscribeStderr failed: %v", err)
+	}
+	defer cancelLines()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := client.GetState(ctx); err != nil {
+		t.Fatalf("GetState failed: %v", err)
+	}
+	waitForStderrLine(t, lines, "stderr line 20")
+
+	if stderr := client.Stderr(); len(stderr) > 64 || !strings.HasSuffix(stderr, "stderr line 20\n") {
+		t.Fatalf("expected stderr bounded to 64 bytes ending in the last line, got %q", stderr)
+	}
+	got := client.StderrLines(2)
+	if len(got) != 2 || got[0] != "stderr line 19" || got[1] != "stderr line 20" {
+		t.Fatalf("unexpected StderrLines(2): %q", got)
+	}
+	for _, line := range client.StderrLines(0) {
+		if !strings.HasPrefix(line, "stderr line ") {
+			t.Fatalf("expected only whole lines, got %q", line)
+		}
+	}
+}
+
+func TestStderrSplitsLinesLongerThanLimit(t *testing.T) {
+	setupFakePI(t, "stderr_long_line")
+
+	opts := testOneShotOptions()
+	opts.StderrLimit = 64
+	client, err := sdk.StartOneShot(opts)
+	if err != nil {
+		t.Fatalf("sdk.StartOneShot failed: %v", err)
+	}
+	defer client.Close()
+
+	lines, cancelLines, err := client.SubscribeStderr(sdk.SubscriptionPolicy{Buffer: 64, Mode: sdk.SubscriptionModeBlock})
+	if err != nil {
+		t.Fatalf("SubscribeStderr failed: %v", err)
+	}
+	defer cancelLines()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := client.GetState(ctx); err != nil {
+		t.Fatalf("GetState failed: %v", err)
+	}
+	for _, want := range []string{strings.Repeat("x", 64), strings.Repeat("x", 64), strings.Repeat("x", 22), "stderr done"} {
+		if line := readStderrLine(t, lines); line.Text != want {
+			t.Fatalf("expected %q, got %q", want, line.Text)
+		}
+	}
+}
+
+func TestSubscribeStderrDeliversLinesAndForwards(t *testing.T) {
+	setupFakePI(t, "stderr_chatter")
+
+	var forwarded syncBuffer
+	var logged syncBuffer
+	opts := testOneShotOptions()
+	opts.StderrWriter = &forwarded
+	opts.StderrLogger = slog.New(slog.NewTextHandler(&logged, nil))
+	client, err := sdk.StartOneShot(opts)
+	if err != nil {
+		t.Fatalf("sdk.StartOneShot failed: %v", err)
+	}
+
+	lines, cancelLines, err := client.SubscribeStderr(sdk.SubscriptionPolicy{Buffer: 64, Mode: sdk.SubscriptionModeBlock})
+	if err != nil {
+		t.Fatalf("SubscribeStderr failed: %v", err)
+	}
+	defer cancelLines()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := client.GetState(ctx); err != nil {
+		t.Fatalf("GetState failed: %v", err)
+	}
+	for index := 1; index <= 20; index++ {
+		line := readStderrLine(t, lines)
+		if want := fmt.Sprintf("stderr line %02d", index); line.Text != want || line.ReceivedAt.IsZero() {
+			t.Fatalf("expected %q, got %+v", want, line)
+		}
+	}
+
+	_ = client.Close()
+	if _, ok := <-lines; ok {
+		t.Fatal("expected stderr subscription to close after Close")
+	}
+	if !strings.Contains(forwarded.String(), "stderr line 01\n") || !strings.Contains(forwarded.String(), "stderr line 20\n") {
+		t.Fatalf("expected raw stderr forwarded to StderrWriter, got %q", forwarded.String())
+	}
+	if !strings.Contains(logged.String(), `line="stderr line 20"`) {
+		t.Fatalf("expected stderr lines logged, got %q", logged.String())
+	}
+}
+
+func waitForStderrLine(t *testing.T, lines <-chan sdk.StderrLine, text string) {
+	t.Helper()
+	for {
+		if line := readStderrLine(t, lines); line.Text == text {
+			return
+		}
+	}
+}
+
+func readStderrLine(t *testing.T, lines <-chan sdk.StderrLine) sdk.StderrLine {
+	t.Helper()
+	select {
+	case line, ok := <-lines:
+		if !ok {
+			t.Fatal("stderr subscription closed")
+		}
+		return line
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for stderr line")
+		return sdk.StderrLine{}
+	}
+}
+
+type syncBuffer struct {
+	mu     sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (buffer *syncBuffer) Write(p []byte) (int, error) {
+	buffer.mu.Lock()
+	defer buffer.mu.Unlock()
+	return buffer.buffer.Write(p)
+}
+
+func (buffer *syncBuffer) String() string {
+	buffer.mu.Lock()
+	defer buffer.mu.Unlock()
+	return buffer.buffer.String()
+}
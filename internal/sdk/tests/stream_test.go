@@ -0,0 +1,167 @@
+package sdk_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/joshp123/pi-golang/internal/sdk"
+)
+
+func TestStreamYieldsDeltasToolBoundariesAndOutcome(t *testing.T) {
+	setupFakePI(t, "stream_run")
+
+	client, err := sdk.StartOneShot(testOneShotOptions())
+	if err != nil {
+		t.Fatalf("sdk.StartOneShot failed: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	var kinds []sdk.StreamChunkKind
+	var text, thinking strings.Builder
+	var outcome *sdk.TerminalOutcome
+	for chunk, err := range client.Stream(ctx, sdk.PromptRequest{Message: "list"}) {
+		if err != nil {
+			t.Fatalf("Stream yielded error: %v", err)
+		}
+		kinds = append(kinds, chunk.Kind)
+		switch chunk.Kind {
+		case sdk.StreamChunkTextDelta:
+			text.WriteString(chunk.Delta)
+		case sdk.StreamChunkThinkingDelta:
+			thinking.WriteString(chunk.Delta)
+		case sdk.StreamChunkToolStart:
+			if chunk.ToolCallID != "call-1" || chunk.ToolName != "bash" || string(chunk.Args) != `{"command":"ls"}` {
+				t.Fatalf("unexpected tool start chunk: %+v", chunk)
+			}
+		case sdk.StreamChunkToolEnd:
+			if chunk.ToolCallID != "call-1" || chunk.IsError || len(chunk.Result) == 0 {
+				t.Fatalf("unexpected tool end chunk: %+v", chunk)
+			}
+		case sdk.StreamChunkOutcome:
+			outcome = chunk.Outcome
+		}
+	}
+
+	want := []sdk.StreamChunkKind{
+		sdk.StreamChunkThinkingDelta, sdk.StreamChunkThinkingDelta,
+		sdk.StreamChunkToolStart, sdk.StreamChunkToolEnd,
+		sdk.StreamChunkTextDelta, sdk.StreamChunkTextDelta,
+		sdk.StreamChunkOutcome,
+	}
+	if !slices.Equal(kinds, want) {
+		t.Fatalf("unexpected chunk kinds: %v", kinds)
+	}
+	if thinking.String() != "need ls" || text.String() != "found go.mod" {
+		t.Fatalf("unexpected deltas: thinking=%q text=%q", thinking.String(), text.String())
+	}
+	if outcome == nil || outcome.Status != sdk.TerminalStatusCompleted || outcome.Text != "found go.mod" {
+		t.Fatalf("unexpected outcome: %+v", outcome)
+	}
+}
+
+func TestStreamEarlyBreakAbortsRunAndReleasesSlot(t *testing.T) {
+	setupFakePI(t, "stream_run")
+
+	client, err := sdk.StartOneShot(testOneShotOptions())
+	if err != nil {
+		t.Fatalf("sdk.StartOneShot failed: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	for chunk, err := range client.Stream(ctx, sdk.PromptRequest{Message: "hold"}) {
+		if err != nil {
+			t.Fatalf("Stream yielded error: %v", err)
+		}
+		if chunk.Kind != sdk.StreamChunkTextDelta || chunk.Delta != "partial" {
+			t.Fatalf("unexpected first chunk: %+v", chunk)
+		}
+		break
+	}
+
+	assertAbortObserved(t, client)
+
+	if _, err := client.Run(ctx, sdk.PromptRequest{Message: "list"}); err != nil {
+		t.Fatalf("Run after early break failed: %v", err)
+	}
+}
+
+func TestStreamContextCancellationAbortsRun(t *testing.T) {
+	setupFakePI(t, "stream_run")
+
+	client, err := sdk.StartOneShot(testOneShotOptions())
+	if err != nil {
+		t.Fatalf("sdk.StartOneShot failed: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var streamErr error
+	for chunk, err := range client.Stream(ctx, sdk.PromptRequest{Message: "hold"}) {
+		if err != nil {
+			streamErr = err
+			continue
+		}
+		if chunk.Kind == sdk.StreamChunkTextDelta {
+			cancel()
+		}
+	}
+	if !errors.Is(streamErr, context.Canceled) {
+		t.Fatalf("expected context cancellation, got %v", streamErr)
+	}
+
+	assertAbortObserved(t, client)
+}
+
+func TestStreamRejectsConcurrentRun(t *testing.T) {
+	setupFakePI(t, "slow_run")
+
+	client, err := sdk.StartOneShot(testOneShotOptions())
+	if err != nil {
+		t.Fatalf("sdk.StartOneShot failed: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	runErr := make(chan error, 1)
+	go func() {
+		_, err := client.Run(ctx, sdk.PromptRequest{Message: "first"})
+		runErr <- err
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	for _, err := range client.Stream(ctx, sdk.PromptRequest{Message: "second"}) {
+		if !errors.Is(err, sdk.ErrRunInProgress) {
+			t.Fatalf("expected sdk.ErrRunInProgress, got %v", err)
+		}
+	}
+	if err := <-runErr; err != nil {
+		t.Fatalf("first run failed: %v", err)
+	}
+}
+
+func assertAbortObserved(t *testing.T, client *sdk.OneShotClient) {
+	t.Helper()
+	stateCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	state, err := client.GetState(stateCtx)
+	if err != nil {
+		t.Fatalf("GetState failed: %v", err)
+	}
+	if state.SessionID != "abort-observed" {
+		t.Fatalf("expected abort after stream stopped, got session id %q", state.SessionID)
+	}
+}
@@ -0,0 +1,41 @@
+package sdk_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sdk "github.com/joshp123/pi-golang/internal/sdk"
+)
+
+type repoSummary struct {
+	Name  string `json:"name"`
+	Stars int    `json:"stars"`
+}
+
+func TestRunJSONRepairsInvalidReply(t *testing.T) {
+	setupFakePI(t, "structured_output")
+
+	client, err := sdk.StartOneShot(testOneShotOptions())
+	if err != nil {
+		t.Fatalf("sdk.StartOneShot failed: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	result, err := sdk.RunJSON[repoSummary](ctx, client, sdk.PromptRequest{Message: "summarize the repo"}, sdk.JSONOptions{})
+	if err != nil {
+		t.Fatalf("RunJSON failed: %v", err)
+	}
+	if result.Value != (repoSummary{Name: "pi", Stars: 5}) {
+		t.Fatalf("unexpected value: %+v", result.Value)
+	}
+	if len(result.Attempts) != 2 {
+		t.Fatalf("expected initial attempt plus one repair, got %d", len(result.Attempts))
+	}
+	if result.Attempts[0].Outcome.Text == result.Attempts[1].Outcome.Text {
+		t.Fatalf("expected distinct attempt outcomes: %+v", result.Attempts)
+	}
+}
@@ -0,0 +1,223 @@
+package sdk_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sdk "github.com/joshp123/pi-golang/internal/sdk"
+)
+
+func TestSupervisorRestartsAndResumesSession(t *testing.T) {
+	setupFakePI(t, "supervised_crash")
+
+	supervisor, err := sdk.StartSupervisor(testSessionOptions(), sdk.SupervisorOptions{InitialBackoff: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("sdk.StartSupervisor failed: %v", err)
+	}
+	defer supervisor.Close()
+
+	events, cancelEvents, err := supervisor.Subscribe(sdk.SubscriptionPolicy{Buffer: 64, Mode: sdk.SubscriptionModeBlock})
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	defer cancelEvents()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := supervisor.Run(ctx, sdk.PromptRequest{Message: "hello"})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.Text != "session=/sessions/original.jsonl resumed=false" {
+		t.Fatalf("unexpected first run text %q", result.Text)
+	}
+
+	if _, err := supervisor.Run(ctx, sdk.PromptRequest{Message: "crash"}); !errors.Is(err, sdk.ErrProcessDied) {
+		t.Fatalf("expected ErrProcessDied for crashing run, got %v", err)
+	}
+
+	restarted := waitForEvent(t, events, sdk.EventTypeProcessRestarted)
+	typed, err := sdk.DecodeEvent(restarted)
+	if err != nil {
+		t.Fatalf("DecodeEvent failed: %v", err)
+	}
+	restart, ok := typed.(sdk.ProcessRestartedEvent)
+	if !ok || restart.Restart != 1 || restart.SessionFile != "/sessions/original.jsonl" || restart.Error == "" {
+		t.Fatalf("unexpected process_restarted event: %#v", typed)
+	}
+
+	result, err = supervisor.Run(ctx, sdk.PromptRequest{Message: "hello"})
+	if err != nil {
+		t.Fatalf("Run after restart failed: %v", err)
+	}
+	if result.Text != "session=/sessions/original.jsonl resumed=true" {
+		t.Fatalf("expected resumed session, got %q", result.Text)
+	}
+	waitForEvent(t, events, sdk.EventTypeAgentEnd)
+
+	if stats := supervisor.Stats(); stats.Restarts != 1 || !stats.Running {
+		t.Fatalf("unexpected supervisor stats: %+v", stats)
+	}
+}
+
+func TestSupervisorResumesSessionChangedBeforeCrash(t *testing.T) {
+	cases := []struct {
+		name    string
+		change  func(context.Context, *sdk.SessionClient) error
+		session string
+	}{
+		{
+			name: "switch",
+			change: func(ctx context.Context, client *sdk.SessionClient) error {
+				_, err := client.SwitchSession(ctx, "/sessions/switched.jsonl")
+				return err
+			},
+			session: "/sessions/switched.jsonl",
+		},
+		{
+			name: "fork",
+			change: func(ctx context.Context, client *sdk.SessionClient) error {
+				_, err := client.Fork(ctx, "entry-1")
+				return err
+			},
+			session: "/sessions/forked.jsonl",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			setupFakePI(t, "supervised_crash")
+
+			supervisor, err := sdk.StartSupervisor(testSessionOptions(), sdk.SupervisorOptions{InitialBackoff: 10 * time.Millisecond})
+			if err != nil {
+				t.Fatalf("sdk.StartSupervisor failed: %v", err)
+			}
+			defer supervisor.Close()
+
+			events, cancelEvents, err := supervisor.Subscribe(sdk.SubscriptionPolicy{Buffer: 64, Mode: sdk.SubscriptionModeBlock})
+			if err != nil {
+				t.Fatalf("Subscribe failed: %v", err)
+			}
+			defer cancelEvents()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			client, err := supervisor.Current(ctx)
+			if err != nil {
+				t.Fatalf("Current failed: %v", err)
+			}
+			if err := tc.change(ctx, client); err != nil {
+				t.Fatalf("session change failed: %v", err)
+			}
+			// No agent_end between the change and the crash.
+			if _, err := supervisor.Run(ctx, sdk.PromptRequest{Message: "crash"}); !errors.Is(err, sdk.ErrProcessDied) {
+				t.Fatalf("expected ErrProcessDied for crashing run, got %v", err)
+			}
+			waitForEvent(t, events, sdk.EventTypeProcessRestarted)
+
+			result, err := supervisor.Run(ctx, sdk.PromptRequest{Message: "hello"})
+			if err != nil {
+				t.Fatalf("Run after restart failed: %v", err)
+			}
+			if want := "session=" + tc.session + " resumed=true"; result.Text != want {
+				t.Fatalf("expected %q, got %q", want, result.Text)
+			}
+		})
+	}
+}
+
+func TestSupervisorGivesUpOnCrashLoop(t *testing.T) {
+	setupFakePI(t, "die_on_prompt")
+
+	supervisor, err := sdk.StartSupervisor(testSessionOptions(), sdk.SupervisorOptions{MaxRestarts: 1, InitialBackoff: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("sdk.StartSupervisor failed: %v", err)
+	}
+	defer supervisor.Close()
+
+	events, cancelEvents, err := supervisor.Subscribe(sdk.SubscriptionPolicy{Buffer: 64, Mode: sdk.SubscriptionModeBlock})
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	defer cancelEvents()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for attempt := range 2 {
+		if _, err := supervisor.Run(ctx, sdk.PromptRequest{Message: "hello"}); !errors.Is(err, sdk.ErrProcessDied) {
+			t.Fatalf("attempt %d: expected ErrProcessDied, got %v", attempt, err)
+		}
+	}
+
+	died := waitForEvent(t, events, sdk.EventTypeProcessDied)
+	if _, err := supervisor.Current(ctx); !errors.Is(err, sdk.ErrCrashLoop) {
+		t.Fatalf("expected ErrCrashLoop, got %v (event %s)", err, died.Raw)
+	}
+	if _, ok := <-events; ok {
+		t.Fatal("expected subscription to close after crash loop")
+	}
+}
+
+func TestSupervisorReplacesCallerClosedClientWithoutCrashLoop(t *testing.T) {
+	setupFakePI(t, "happy")
+
+	supervisor, err := sdk.StartSupervisor(testSessionOptions(), sdk.SupervisorOptions{MaxRestarts: 1, InitialBackoff: time.Hour})
+	if err != nil {
+		t.Fatalf("sdk.StartSupervisor failed: %v", err)
+	}
+	defer supervisor.Close()
+
+	events, cancelEvents, err := supervisor.Subscribe(sdk.SubscriptionPolicy{Buffer: 64, Mode: sdk.SubscriptionModeBlock})
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	defer cancelEvents()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// More closes than MaxRestarts, each replaced without the hour-long backoff.
+	for attempt := range 3 {
+		client, err := supervisor.Current(ctx)
+		if err != nil {
+			t.Fatalf("attempt %d: Current failed: %v", attempt, err)
+		}
+		_ = client.Close()
+		waitForEvent(t, events, sdk.EventTypeProcessRestarted)
+	}
+
+	if _, err := supervisor.Run(ctx, sdk.PromptRequest{Message: "hello"}); err != nil {
+		t.Fatalf("Run after caller closes failed: %v", err)
+	}
+	if err := supervisor.Err(); err != nil {
+		t.Fatalf("expected supervisor to keep running, got %v", err)
+	}
+}
+
+func testSessionOptions() sdk.SessionOptions {
+	opts := sdk.DefaultSessionOptions()
+	opts.Auth.Anthropic.APIKey = sdk.Credential{Value: "test-key"}
+	return opts
+}
+
+func waitForEvent(t *testing.T, events <-chan sdk.Event, eventType string) sdk.Event {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				t.Fatalf("event stream closed before %s", eventType)
+			}
+			if event.Type == eventType {
+				return event
+			}
+		case <-timeout:
+			t.Fatalf("timeout waiting for %s", eventType)
+		}
+	}
+}
@@ -31,6 +31,38 @@ func TestDecodeTerminalOutcomeCompleted(t *testing.T) {
 	}
 }
 
+func TestDecodeTerminalOutcomeThinkingAndToolCalls(t *testing.T) {
+	raw := json.RawMessage(`{
+		"type":"agent_end",
+		"messages":[
+			{"role":"assistant","content":[
+				{"type":"thinking","thinking":"check "},
+				{"type":"text","text":"running "},
+				{"type":"thinking","thinking":"tests"},
+				{"type":"toolCall","id":"call-1","name":"bash","arguments":{"command":"go test"}},
+				{"type":"text","text":"tests"}
+			],"stopReason":"aborted"}
+		]
+	}`)
+
+	outcome, err := sdk.DecodeTerminalOutcome(raw)
+	if err != nil {
+		t.Fatalf("DecodeTerminalOutcome returned error: %v", err)
+	}
+	if outcome.Text != "running tests" {
+		t.Fatalf("expected concatenated text, got %q", outcome.Text)
+	}
+	if outcome.Thinking != "check tests" {
+		t.Fatalf("expected concatenated thinking, got %q", outcome.Thinking)
+	}
+	if len(outcome.ToolCalls) != 1 || outcome.ToolCalls[0].ID != "call-1" || outcome.ToolCalls[0].Name != "bash" {
+		t.Fatalf("unexpected tool calls: %+v", outcome.ToolCalls)
+	}
+	if string(outcome.ToolCalls[0].Arguments) != `{"command":"go test"}` {
+		t.Fatalf("unexpected tool call arguments: %s", outcome.ToolCalls[0].Arguments)
+	}
+}
+
 func TestDecodeTerminalOutcomeAborted(t *testing.T) {
 	raw := json.RawMessage(`{
 		"type":"agent_end",
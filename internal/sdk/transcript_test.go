@@ -0,0 +1,181 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/joshp123/pi-golang/internal/testsupport"
+)
+
+func TestAgentMessageBlocksDecodesContentVariants(t *testing.T) {
+	message := AgentMessage{Role: "assistant", Content: json.RawMessage(`[
+		{"type":"thinking","thinking":"plan"},
+		{"type":"text","text":"hello"},
+		{"type":"image","data":"aGk=","mimeType":"image/png"},
+		{"type":"toolCall","id":"call-1","name":"bash","arguments":{"command":"ls"}},
+		{"type":"redacted","data":"x"}
+	]`)}
+
+	blocks, err := message.Blocks()
+	if err != nil {
+		t.Fatalf("Blocks returned error: %v", err)
+	}
+	if len(blocks) != 5 {
+		t.Fatalf("expected 5 blocks, got %d", len(blocks))
+	}
+	if block, ok := blocks[0].(ThinkingBlock); !ok || block.Thinking != "plan" {
+		t.Fatalf("unexpected thinking block: %#v", blocks[0])
+	}
+	if block, ok := blocks[1].(TextBlock); !ok || block.Text != "hello" {
+		t.Fatalf("unexpected text block: %#v", blocks[1])
+	}
+	if block, ok := blocks[2].(ImageBlock); !ok || block.MIMEType != "image/png" || block.Data != "aGk=" {
+		t.Fatalf("unexpected image block: %#v", blocks[2])
+	}
+	call, ok := blocks[3].(ToolCallBlock)
+	if !ok || call.ID != "call-1" || call.Name != "bash" || string(call.Arguments) != `{"command":"ls"}` {
+		t.Fatalf("unexpected tool call block: %#v", blocks[3])
+	}
+	unknown, ok := blocks[4].(UnknownBlock)
+	if !ok || unknown.BlockType() != "redacted" || !strings.Contains(string(unknown.Raw), `"data":"x"`) {
+		t.Fatalf("unexpected unknown block: %#v", blocks[4])
+	}
+}
+
+func TestAgentMessageBlocksHandlesStringAndEmptyContent(t *testing.T) {
+	blocks, err := AgentMessage{Role: "user", Content: json.RawMessage(`"hi"`)}.Blocks()
+	if err != nil {
+		t.Fatalf("Blocks returned error: %v", err)
+	}
+	if len(blocks) != 1 || blocks[0] != (TextBlock{Text: "hi"}) {
+		t.Fatalf("unexpected string content blocks: %#v", blocks)
+	}
+
+	blocks, err = AgentMessage{Role: "user"}.Blocks()
+	if err != nil || blocks != nil {
+		t.Fatalf("expected no blocks for empty content, got %#v, %v", blocks, err)
+	}
+}
+
+func TestDecodeContentBlocksRejectsMalformedBlocks(t *testing.T) {
+	for _, content := range []string{
+		`[{"text":"missing type"}]`,
+		`[{"type":"toolCall","name":"bash"}]`,
+	} {
+		if _, err := DecodeContentBlocks(json.RawMessage(content)); !errors.Is(err, ErrProtocolViolation) {
+			t.Fatalf("expected protocol violation for %s, got %v", content, err)
+		}
+	}
+}
+
+func TestDecodeMessagesRequiresRole(t *testing.T) {
+	if _, err := decodeMessages(json.RawMessage(`{}`)); !errors.Is(err, ErrProtocolViolation) {
+		t.Fatalf("expected protocol violation for missing messages, got %v", err)
+	}
+	if _, err := decodeMessages(json.RawMessage(`{"messages":[{"content":"hi"}]}`)); !errors.Is(err, ErrProtocolViolation) {
+		t.Fatalf("expected protocol violation for missing role, got %v", err)
+	}
+}
+
+func TestGetMessagesAndTranscriptRendering(t *testing.T) {
+	testsupport.SetupFakePI(t, "happy")
+
+	client, err := StartOneShot(testModelOneShotOptions())
+	if err != nil {
+		t.Fatalf("StartOneShot returned error: %v", err)
+	}
+	defer client.Close()
+
+	messages, err := client.GetMessages(context.Background())
+	if err != nil {
+		t.Fatalf("GetMessages returned error: %v", err)
+	}
+	if len(messages) != 4 {
+		t.Fatalf("expected 4 messages, got %d", len(messages))
+	}
+	if messages[0].Timestamp != 1700000000000 {
+		t.Fatalf("unexpected timestamp: %d", messages[0].Timestamp)
+	}
+	if messages[2].Role != "toolResult" || messages[2].ToolCallID != "call-1" || messages[2].ToolName != "bash" {
+		t.Fatalf("unexpected tool result message: %+v", messages[2])
+	}
+
+	transcript, err := client.GetTranscript(context.Background())
+	if err != nil {
+		t.Fatalf("GetTranscript returned error: %v", err)
+	}
+	if len(transcript.Entries) != 4 || len(transcript.Entries[1].Blocks) != 3 {
+		t.Fatalf("unexpected transcript entries: %+v", transcript.Entries)
+	}
+
+	text := transcript.Text()
+	for _, want := range []string{
+		"User @ 2023-11-14T22:13:20Z:\nlist the repo",
+		"[thinking] I should run ls.",
+		`[tool call bash (call-1)] {"command":"ls"}`,
+		"Tool result bash (call-1) @ 2023-11-14T22:13:22Z:\nREADME.md\ngo.mod",
+		"The repo has a README and go.mod.",
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("text transcript missing %q:\n%s", want, text)
+		}
+	}
+
+	markdown := transcript.Markdown()
+	for _, want := range []string{
+		"## Assistant @ 2023-11-14T22:13:21Z",
+		"> **Thinking**\n>\n> I should run ls.",
+		"**Tool call** `bash` (call-1)\n\n```json\n{\"command\":\"ls\"}\n```",
+		"## Tool result `bash` (call-1) @ 2023-11-14T22:13:22Z\n\n```\nREADME.md\ngo.mod\n```",
+	} {
+		if !strings.Contains(markdown, want) {
+			t.Fatalf("markdown transcript missing %q:\n%s", want, markdown)
+		}
+	}
+}
+
+func TestTranscriptMarkdownWidensFenceAroundBackticks(t *testing.T) {
+	transcript, err := NewTranscript([]AgentMessage{{
+		Role:       "toolResult",
+		ToolCallID: "call-1",
+		ToolName:   "read",
+		IsError:    true,
+		Content:    json.RawMessage(`[{"type":"text","text":"` + "```go\\nfmt.Println()\\n```" + `"}]`),
+	}})
+	if err != nil {
+		t.Fatalf("NewTranscript returned error: %v", err)
+	}
+	markdown := transcript.Markdown()
+	if !strings.Contains(markdown, "## Tool result `read` (call-1) [error]") {
+		t.Fatalf("missing error heading:\n%s", markdown)
+	}
+	if !strings.Contains(markdown, "````\n```go\nfmt.Println()\n```\n````") {
+		t.Fatalf("expected widened fence:\n%s", markdown)
+	}
+}
+
+func TestTranscriptRendersBashExecutionAndSkipsEmptyMessages(t *testing.T) {
+	exitCode := 2
+	transcript, err := NewTranscript([]AgentMessage{
+		{Role: "bashExecution", Command: "make test", Output: "FAIL ./...\n", ExitCode: &exitCode},
+		{Role: "compactionSummary"},
+		{Role: "user", Content: json.RawMessage(`"fix it"`)},
+	})
+	if err != nil {
+		t.Fatalf("NewTranscript returned error: %v", err)
+	}
+
+	if text := transcript.Text(); text != "Bash (exit 2):\n$ make test\nFAIL ./...\n\nUser:\nfix it\n" {
+		t.Fatalf("unexpected text transcript:\n%q", text)
+	}
+	markdown := transcript.Markdown()
+	if !strings.Contains(markdown, "## Bash (exit 2)\n\n```console\n$ make test\nFAIL ./...\n```") {
+		t.Fatalf("markdown transcript missing bash execution:\n%s", markdown)
+	}
+	if strings.Contains(markdown, "compactionSummary") {
+		t.Fatalf("expected empty message to be skipped:\n%s", markdown)
+	}
+}
@@ -1,20 +1,43 @@
 package sdk
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"time"
+)
 
 type Event struct {
 	Type string          `json:"type"`
 	Raw  json.RawMessage `json:"-"`
+	// ReceivedAt is when the SDK read the line from pi (or synthesized the
+	// event). It carries a monotonic reading, so Sub between events is exact.
+	ReceivedAt time.Time `json:"-"`
+}
+
+// StderrLine is one line of pi stderr, without its trailing newline.
+type StderrLine struct {
+	Text       string
+	ReceivedAt time.Time
 }
 
 const (
+	EventTypeAgentStart          = "agent_start"
 	EventTypeAgentEnd            = "agent_end"
+	EventTypeTurnStart           = "turn_start"
+	EventTypeTurnEnd             = "turn_end"
+	EventTypeMessageStart        = "message_start"
 	EventTypeMessageUpdate       = "message_update"
+	EventTypeMessageEnd          = "message_end"
 	EventTypeAutoCompactionStart = "auto_compaction_start"
 	EventTypeAutoCompactionEnd   = "auto_compaction_end"
 	EventTypeAutoRetryStart      = "auto_retry_start"
 	EventTypeAutoRetryEnd        = "auto_retry_end"
+	EventTypeToolExecutionStart  = "tool_execution_start"
+	EventTypeToolExecutionUpdate = "tool_execution_update"
+	EventTypeToolExecutionEnd    = "tool_execution_end"
+	EventTypeExtensionUIRequest  = "extension_ui_request"
+	EventTypeToolCallDenied      = "tool_call_denied"
 	EventTypeProcessDied         = "process_died"
+	EventTypeProcessRestarted    = "process_restarted"
 	EventTypeSubscriptionDrop    = "subscription_drop"
 )
 
@@ -87,8 +110,11 @@ const (
 type TerminalReason string
 
 type TerminalOutcome struct {
-	Status         TerminalStatus
+	Status TerminalStatus
+	// Text and Thinking concatenate the final assistant message's text and thinking blocks.
 	Text           string
+	Thinking       string
+	ToolCalls      []ToolCallBlock
 	StopReason     string
 	TerminalReason TerminalReason
 	ErrorMessage   string
@@ -96,11 +122,162 @@ type TerminalOutcome struct {
 }
 
 type RunDetailedResult struct {
-	Outcome             TerminalOutcome
+	Outcome TerminalOutcome
+	// AutoCompactionStart/End and AutoRetryStart/End are the last events of each
+	// kind seen; Compactions and Retries keep every episode in order.
 	AutoCompactionStart *AutoCompactionStartEvent
 	AutoCompactionEnd   *AutoCompactionEndEvent
 	AutoRetryStart      *AutoRetryStartEvent
 	AutoRetryEnd        *AutoRetryEndEvent
+	Compactions         []CompactionEpisode
+	Retries             []RetryAttempt
+	ToolCalls           []ToolExecution
+	Turns               []TurnSummary
+	Denials             []ToolCallDeniedEvent
+	// UsageDelta is usage consumed by this run, computed from get_session_stats
+	// before and after it (fields that shrank clamp to zero). When either stats
+	// call fails it falls back to the sum of turn_end assistant usage, which
+	// counts only assistant turns and carries per-field Cost. Nil when neither
+	// source reports usage.
+	UsageDelta *Usage
+	// QueueWait is the time spent waiting for the run slot (RunQueue only).
+	QueueWait time.Duration
+	Timing    RunTiming
+}
+
+// RunTiming holds run latencies measured from run start (just before the
+// prompt is sent) using Event.ReceivedAt. A zero duration means the milestone
+// was not observed.
+type RunTiming struct {
+	PromptAck      time.Duration
+	FirstTextDelta time.Duration
+	FirstToolCall  time.Duration
+	Total          time.Duration
+	// Compaction sums completed auto-compaction episodes; RetryDelay sums the
+	// backoff delays announced by auto_retry_start.
+	Compaction time.Duration
+	RetryDelay time.Duration
+	// OutputTokensPerSecond is the final Usage.Output over the final assistant
+	// message's streaming time (message_start to message_end).
+	OutputTokensPerSecond float64
+}
+
+// RunOptions tunes one RunDetailedWithOptions call. The zero value behaves like RunDetailed.
+type RunOptions struct {
+	Budget Budget
+	// IdleTimeout fails the run with *RunStalledError when no event arrives for
+	// this long (0 disables). Outstanding approvals and bridged tool calls do not
+	// count as idle. The run is aborted first; if agent_end does not follow
+	// within AbortGrace the process is killed.
+	IdleTimeout time.Duration
+	// AbortGrace bounds the wait for agent_end after an SDK-initiated abort
+	// (budget or idle timeout). Default 5s.
+	AbortGrace time.Duration
+}
+
+// Budget caps one run; zero fields are unlimited. Token and cost limits count
+// streamed assistant usage across all turns of the run (input includes cache
+// reads/writes). Tripping any limit aborts the run.
+type Budget struct {
+	MaxInputTokens  int
+	MaxOutputTokens int
+	MaxCostUSD      float64
+	MaxTurns        int
+	MaxToolCalls    int
+	MaxWallTime     time.Duration
+}
+
+type BudgetLimit string
+
+const (
+	BudgetLimitInputTokens  BudgetLimit = "input_tokens"
+	BudgetLimitOutputTokens BudgetLimit = "output_tokens"
+	BudgetLimitCost         BudgetLimit = "cost_usd"
+	BudgetLimitTurns        BudgetLimit = "turns"
+	BudgetLimitToolCalls    BudgetLimit = "tool_calls"
+	BudgetLimitWallTime     BudgetLimit = "wall_time"
+)
+
+// BudgetUsage is what a run consumed, measured against its Budget.
+type BudgetUsage struct {
+	InputTokens  int
+	OutputTokens int
+	CostUSD      float64
+	Turns        int
+	ToolCalls    int
+	WallTime     time.Duration
+}
+
+// RunQueueStats is a point-in-time view of the client run slot.
+type RunQueueStats struct {
+	Enabled bool
+	Running bool
+	Waiting int
+}
+
+type StreamChunkKind string
+
+const (
+	StreamChunkTextDelta     StreamChunkKind = "text_delta"
+	StreamChunkThinkingDelta StreamChunkKind = "thinking_delta"
+	StreamChunkToolStart     StreamChunkKind = "tool_start"
+	StreamChunkToolEnd       StreamChunkKind = "tool_end"
+	StreamChunkOutcome       StreamChunkKind = "outcome"
+)
+
+// StreamChunk is one item yielded by Client.Stream. Fields are set per Kind:
+// Delta for text/thinking deltas, tool fields for tool start/end, Outcome last.
+type StreamChunk struct {
+	Kind       StreamChunkKind
+	Delta      string
+	ToolCallID string
+	ToolName   string
+	Args       json.RawMessage
+	Result     json.RawMessage
+	IsError    bool
+	Outcome    *TerminalOutcome
+}
+
+// TurnSummary is one turn_end observed during a run: the assistant message plus
+// the tool results it produced. Index is 0-based in run order.
+type TurnSummary struct {
+	Index       int
+	Message     AgentMessage
+	ToolResults []AgentMessage
+	Usage       *Usage
+}
+
+// ToolExecution pairs tool_execution_start/update/end events by tool call ID.
+// Completed is false when the run ended before tool_execution_end arrived.
+type ToolExecution struct {
+	ToolCallID    string
+	ToolName      string
+	Args          json.RawMessage
+	PartialResult json.RawMessage
+	Result        json.RawMessage
+	IsError       bool
+	Completed     bool
+	Duration      time.Duration
+}
+
+// CompactionEpisode pairs auto_compaction_start with its auto_compaction_end.
+// StartedAt/EndedAt are offsets from run start; End is nil when the run ended
+// mid-compaction.
+type CompactionEpisode struct {
+	Start     AutoCompactionStartEvent
+	End       *AutoCompactionEndEvent
+	StartedAt time.Duration
+	EndedAt   time.Duration
+}
+
+// RetryAttempt is one auto_retry_start. Upstream sends a single auto_retry_end
+// per retry sequence, so End is set on the sequence's last attempt only; earlier
+// attempts failed and were superseded. Offsets are from run start.
+type RetryAttempt struct {
+	Start     AutoRetryStartEvent
+	End       *AutoRetryEndEvent
+	StartedAt time.Duration
+	EndedAt   time.Duration
 }
 
 type CompletionClass string
@@ -112,10 +289,17 @@ const (
 	CompletionClassFailed          CompletionClass = "failed"
 )
 
+// RecoveryFacts summarises every compaction and retry episode of a run.
+// Recovered means the run completed after at least one overflow compaction
+// succeeded or one retry sequence ended successfully.
 type RecoveryFacts struct {
-	CompactionObserved bool
-	OverflowDetected   bool
-	Recovered          bool
+	CompactionObserved  bool
+	OverflowDetected    bool
+	Recovered           bool
+	Compactions         int
+	OverflowCompactions int
+	RetryAttempts       int
+	RetriesRecovered    int
 }
 
 type ManagedSummary struct {
@@ -150,20 +334,38 @@ type ModelInfo struct {
 	Cost          *Cost    `json:"cost,omitempty"`
 }
 
+type ThinkingLevel string
+
+const (
+	ThinkingLevelOff     ThinkingLevel = "off"
+	ThinkingLevelMinimal ThinkingLevel = "minimal"
+	ThinkingLevelLow     ThinkingLevel = "low"
+	ThinkingLevelMedium  ThinkingLevel = "medium"
+	ThinkingLevelHigh    ThinkingLevel = "high"
+	ThinkingLevelXHigh   ThinkingLevel = "xhigh"
+)
+
+// ModelCycleResult is the cycle_model response. Model is nil when only one model is available.
+type ModelCycleResult struct {
+	Model         *ModelInfo
+	ThinkingLevel ThinkingLevel
+	IsScoped      bool
+}
+
 type SessionState struct {
-	Model                 *ModelInfo `json:"model,omitempty"`
-	ThinkingLevel         string     `json:"thinkingLevel,omitempty"`
-	IsStreaming           bool       `json:"isStreaming"`
-	IsCompacting          bool       `json:"isCompacting"`
-	SteeringMode          string     `json:"steeringMode,omitempty"`
-	FollowUpMode          string     `json:"followUpMode,omitempty"`
-	SessionID             string     `json:"sessionId"`
-	SessionFile           string     `json:"sessionFile,omitempty"`
-	SessionName           string     `json:"sessionName,omitempty"`
-	AutoCompactionEnabled bool       `json:"autoCompactionEnabled"`
-	MessageCount          int        `json:"messageCount"`
-	PendingMessageCount   int        `json:"pendingMessageCount"`
-	ContextWindow         int        `json:"-"`
+	Model                 *ModelInfo    `json:"model,omitempty"`
+	ThinkingLevel         ThinkingLevel `json:"thinkingLevel,omitempty"`
+	IsStreaming           bool          `json:"isStreaming"`
+	IsCompacting          bool          `json:"isCompacting"`
+	SteeringMode          string        `json:"steeringMode,omitempty"`
+	FollowUpMode          string        `json:"followUpMode,omitempty"`
+	SessionID             string        `json:"sessionId"`
+	SessionFile           string        `json:"sessionFile,omitempty"`
+	SessionName           string        `json:"sessionName,omitempty"`
+	AutoCompactionEnabled bool          `json:"autoCompactionEnabled"`
+	MessageCount          int           `json:"messageCount"`
+	PendingMessageCount   int           `json:"pendingMessageCount"`
+	ContextWindow         int           `json:"-"`
 }
 
 type CompactResult struct {
@@ -173,6 +375,43 @@ type CompactResult struct {
 	Details          json.RawMessage `json:"details,omitempty"`
 }
 
+// ForkResult is the upstream fork result. Text is the selected user message
+// (useful to prefill an edited prompt); Cancelled is true when an extension
+// cancelled the fork.
+type ForkResult struct {
+	Text      string `json:"text"`
+	Cancelled bool   `json:"cancelled"`
+}
+
+// ForkMessage is one user message that can be forked from.
+type ForkMessage struct {
+	EntryID string `json:"entryId"`
+	Text    string `json:"text"`
+}
+
+// SessionStats mirrors upstream get_session_stats. Usage holds cumulative
+// session tokens (TotalTokens) and cost (Cost.Total only).
+type SessionStats struct {
+	SessionFile       string
+	SessionID         string
+	UserMessages      int
+	AssistantMessages int
+	ToolCalls         int
+	ToolResults       int
+	TotalMessages     int
+	Usage             Usage
+}
+
+// BashResult is the upstream bash command result. ExitCode is nil when the
+// process was killed before exiting (e.g. cancelled via abort_bash).
+type BashResult struct {
+	Output         string `json:"output"`
+	ExitCode       *int   `json:"exitCode,omitempty"`
+	Cancelled      bool   `json:"cancelled"`
+	Truncated      bool   `json:"truncated"`
+	FullOutputPath string `json:"fullOutputPath,omitempty"`
+}
+
 type SkillLocation string
 
 const (
@@ -197,8 +436,20 @@ type AgentMessage struct {
 	TerminalReason    TerminalReason  `json:"terminalReason,omitempty"`
 	TerminalReasonAlt TerminalReason  `json:"terminal_reason,omitempty"`
 	ErrorMessage      string          `json:"errorMessage,omitempty"`
+	ToolCallID        string          `json:"toolCallId,omitempty"`
+	ToolName          string          `json:"toolName,omitempty"`
+	IsError           bool            `json:"isError,omitempty"`
+	// Timestamp is Unix milliseconds when present.
+	Timestamp int64 `json:"timestamp,omitempty"`
+	// Command, Output and ExitCode are set on bashExecution messages, which
+	// carry no content.
+	Command  string `json:"command,omitempty"`
+	Output   string `json:"output,omitempty"`
+	ExitCode *int   `json:"exitCode,omitempty"`
 }
 
+type AgentStartEvent struct{}
+
 type AgentEndEvent struct {
 	Messages []AgentMessage `json:"messages"`
 }
@@ -213,6 +464,21 @@ type AssistantMessageDelta struct {
 	Raw          json.RawMessage `json:"-"`
 }
 
+type TurnStartEvent struct{}
+
+type TurnEndEvent struct {
+	Message     AgentMessage   `json:"message"`
+	ToolResults []AgentMessage `json:"toolResults"`
+}
+
+type MessageStartEvent struct {
+	Message AgentMessage `json:"message"`
+}
+
+type MessageEndEvent struct {
+	Message AgentMessage `json:"message"`
+}
+
 type MessageUpdateEvent struct {
 	Message               AgentMessage          `json:"message"`
 	AssistantMessageEvent AssistantMessageDelta `json:"assistantMessageEvent"`
@@ -241,3 +507,60 @@ type AutoRetryEndEvent struct {
 	Attempt    int    `json:"attempt"`
 	FinalError string `json:"finalError,omitempty"`
 }
+
+type ToolExecutionStartEvent struct {
+	ToolCallID string          `json:"toolCallId"`
+	ToolName   string          `json:"toolName"`
+	Args       json.RawMessage `json:"args,omitempty"`
+}
+
+type ToolExecutionUpdateEvent struct {
+	ToolCallID    string          `json:"toolCallId"`
+	ToolName      string          `json:"toolName"`
+	Args          json.RawMessage `json:"args,omitempty"`
+	PartialResult json.RawMessage `json:"partialResult,omitempty"`
+}
+
+type ToolExecutionEndEvent struct {
+	ToolCallID string          `json:"toolCallId"`
+	ToolName   string          `json:"toolName"`
+	Result     json.RawMessage `json:"result,omitempty"`
+	IsError    bool            `json:"isError"`
+}
+
+// ToolCallDeniedEvent is the SDK-emitted tool_call_denied event published when
+// ApproveToolCall denies (or fails closed on) a tool call.
+type ToolCallDeniedEvent struct {
+	ToolCallID string          `json:"toolCallId"`
+	ToolName   string          `json:"toolName"`
+	Input      json.RawMessage `json:"input,omitempty"`
+	Reason     string          `json:"reason,omitempty"`
+}
+
+type UIMethod string
+
+const (
+	UIMethodSelect    UIMethod = "select"
+	UIMethodConfirm   UIMethod = "confirm"
+	UIMethodInput     UIMethod = "input"
+	UIMethodEditor    UIMethod = "editor"
+	UIMethodNotify    UIMethod = "notify"
+	UIMethodSetStatus UIMethod = "setStatus"
+)
+
+// ExtensionUIRequest is an upstream extension_ui_request frame.
+// Dialog methods (select, confirm, input, editor) expect an extension_ui_response;
+// other methods are fire-and-forget.
+type ExtensionUIRequest struct {
+	ID          string   `json:"id"`
+	Method      UIMethod `json:"method"`
+	Title       string   `json:"title,omitempty"`
+	Message     string   `json:"message,omitempty"`
+	Options     []string `json:"options,omitempty"`
+	Placeholder string   `json:"placeholder,omitempty"`
+	Prefill     string   `json:"prefill,omitempty"`
+	TimeoutMS   int      `json:"timeout,omitempty"`
+	NotifyType  string   `json:"notifyType,omitempty"`
+	StatusKey   string   `json:"statusKey,omitempty"`
+	StatusText  string   `json:"statusText,omitempty"`
+}
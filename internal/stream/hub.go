@@ -10,6 +10,7 @@ type Hub[T any] struct {
 	eventType     func(T) string
 	dropEventType string
 	newDropEvent  func(Mode, string) T
+	onDrop        func(Mode, T)
 }
 
 func NewHub[T any](closedErr error, eventType func(T) string, dropEventType string, newDropEvent func(Mode, string) T) *Hub[T] {
@@ -22,6 +23,14 @@ func NewHub[T any](closedErr error, eventType func(T) string, dropEventType stri
 	}
 }
 
+// OnDrop registers observer to run for every event a subscriber drops,
+// whether or not its policy emits drop events.
+func (hub *Hub[T]) OnDrop(observer func(Mode, T)) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	hub.onDrop = observer
+}
+
 func (hub *Hub[T]) Subscribe(policy Policy) (<-chan T, func(), error) {
 	sub := newSubscription[T](policy)
 
@@ -94,8 +103,14 @@ func (hub *Hub[T]) closeAndSnapshot() map[*subscription[T]]struct{} {
 }
 
 func (hub *Hub[T]) publishToSubscribers(subscribers map[*subscription[T]]struct{}, event T) {
+	hub.mu.Lock()
+	onDrop := hub.onDrop
+	hub.mu.Unlock()
 	for sub := range subscribers {
 		dropped := sub.enqueue(event)
+		if dropped && onDrop != nil {
+			onDrop(sub.policy.Mode, event)
+		}
 		if dropped && sub.policy.EmitDropEvent && hub.newDropEvent != nil && hub.eventType != nil {
 			if eventType := hub.eventType(event); eventType != "" && eventType != hub.dropEventType {
 				sub.enqueueSystem(hub.newDropEvent(sub.policy.Mode, eventType))
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
 )
 
 const (
@@ -15,22 +16,58 @@ const (
 	commandCompact     = "compact"
 	commandGetCommands = "get_commands"
 
+	commandSetModel           = "set_model"
+	commandCycleModel         = "cycle_model"
+	commandGetAvailableModels = "get_available_models"
+	commandSetThinkingLevel   = "set_thinking_level"
+	commandCycleThinkingLevel = "cycle_thinking_level"
+	commandBash               = "bash"
+	commandAbortBash          = "abort_bash"
+	commandGetSessionStats    = "get_session_stats"
+	commandSwitchSession      = "switch_session"
+	commandFork               = "fork"
+	commandGetForkMessages    = "get_fork_messages"
+	commandGetMessages        = "get_messages"
+
+	commandExtensionUIResponse = "extension_ui_response"
+
 	eventTypeResponse            = "response"
+	eventTypeAgentStart          = "agent_start"
 	eventTypeAgentEnd            = "agent_end"
+	eventTypeTurnStart           = "turn_start"
+	eventTypeTurnEnd             = "turn_end"
+	eventTypeMessageStart        = "message_start"
 	eventTypeMessageUpdate       = "message_update"
+	eventTypeMessageEnd          = "message_end"
 	eventTypeAutoCompactionStart = "auto_compaction_start"
 	eventTypeAutoCompactionEnd   = "auto_compaction_end"
 	eventTypeAutoRetryStart      = "auto_retry_start"
 	eventTypeAutoRetryEnd        = "auto_retry_end"
+	eventTypeToolExecutionStart  = "tool_execution_start"
+	eventTypeToolExecutionUpdate = "tool_execution_update"
+	eventTypeToolExecutionEnd    = "tool_execution_end"
+	eventTypeExtensionUIRequest  = "extension_ui_request"
+
+	toolBridgeFileEnv   = "PI_GOLANG_TOOLS_FILE"
+	toolBridgeUITitle   = "pi-golang:tool-call"
+	toolBridgeCancelKey = "pi-golang:tool-cancel"
+
+	approvalGateUITitle = "pi-golang:approve-tool-call"
 )
 
-func RunScenario(scenario string, processArgs []string, stdin io.Reader, stdout io.Writer) error {
+func RunScenario(scenario string, processArgs []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
 	scanner := bufio.NewScanner(stdin)
 	writer := bufio.NewWriter(stdout)
 	defer writer.Flush()
 
+	happy := newHappyState()
 	abortRun := abortRunState{}
 	runCancelAbort := runCancelAbortState{}
+	customTools := editorBridgeState{}
+	approvalGate := editorBridgeState{}
+	bashAbort := bashAbortState{}
+	streamRun := streamRunState{}
+	supervisedSession := ""
 	skillPaths := collectFlagValues(processArgs, "--skill")
 
 	for scanner.Scan() {
@@ -76,8 +113,23 @@ func RunScenario(scenario string, processArgs []string, stdin io.Reader, stdout
 			if err := writeResponse(writer, requestID, commandType, true, map[string]any{}, ""); err != nil {
 				return err
 			}
+		case "exit_on_prompt":
+			if commandType == commandPrompt {
+				return fmt.Errorf("fatal: model runtime crashed")
+			}
+			if err := writeResponse(writer, requestID, commandType, true, map[string]any{}, ""); err != nil {
+				return err
+			}
+		case "stderr_chatter":
+			if err := handleStderrChatterScenario(writer, stderr, requestID, commandType); err != nil {
+				return err
+			}
+		case "stderr_long_line":
+			if err := handleStderrLongLineScenario(writer, stderr, requestID, commandType); err != nil {
+				return err
+			}
 		case "happy", "skills_unexpected":
-			if err := handleHappyScenario(writer, requestID, commandType, command); err != nil {
+			if err := handleHappyScenario(writer, &happy, requestID, commandType, command); err != nil {
 				return err
 			}
 		case "prompt_async_error":
@@ -104,6 +156,61 @@ func RunScenario(scenario string, processArgs []string, stdin io.Reader, stdout
 			if err := handleRunDetailedSignalsScenario(writer, requestID, commandType); err != nil {
 				return err
 			}
+		case "run_recovery_timeline":
+			if err := handleRunRecoveryTimelineScenario(writer, requestID, commandType); err != nil {
+				return err
+			}
+		case "run_timing":
+			if err := handleRunTimingScenario(writer, requestID, commandType); err != nil {
+				return err
+			}
+		case "run_tool_calls":
+			if err := handleRunToolCallsScenario(writer, requestID, commandType); err != nil {
+				return err
+			}
+		case "ui_select", "ui_confirm", "ui_input", "ui_editor", "ui_notify", "ui_status_burst":
+			if err := handleExtensionUIScenario(writer, strings.TrimPrefix(scenario, "ui_"), requestID, commandType, command); err != nil {
+				return err
+			}
+		case "custom_tools":
+			if err := handleCustomToolsScenario(writer, &customTools, requestID, commandType, command); err != nil {
+				return err
+			}
+		case "custom_tools_cancel":
+			if err := handleCustomToolsCancelScenario(writer, requestID, commandType, command); err != nil {
+				return err
+			}
+		case "approval_gate":
+			if err := handleApprovalGateScenario(writer, &approvalGate, requestID, commandType, command); err != nil {
+				return err
+			}
+		case "bash_abort":
+			if err := handleBashAbortScenario(writer, &bashAbort, requestID, commandType); err != nil {
+				return err
+			}
+		case "stream_run":
+			if err := handleStreamRunScenario(writer, &streamRun, requestID, commandType, command); err != nil {
+				return err
+			}
+		case "structured_output":
+			if err := handleStructuredOutputScenario(writer, requestID, commandType, command); err != nil {
+				return err
+			}
+		case "supervised_crash":
+			if message, _ := command["message"].(string); commandType == commandPrompt && message == "crash" {
+				return nil
+			}
+			if err := handleSupervisedCrashScenario(writer, &supervisedSession, collectFlagValues(processArgs, "--session"), requestID, commandType, command); err != nil {
+				return err
+			}
+		case "budget_run":
+			if err := handleBudgetRunScenario(writer, requestID, commandType, command); err != nil {
+				return err
+			}
+		case "run_hang":
+			if err := handleRunHangScenario(writer, requestID, commandType); err != nil {
+				return err
+			}
 		case "never_respond":
 			continue
 		default:
@@ -4,23 +4,112 @@ import (
 	"bufio"
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"strings"
 	"time"
 )
 
-func handleHappyScenario(writer *bufio.Writer, requestID string, commandType string, command map[string]any) error {
+type happyState struct {
+	sessionFile string
+	provider    string
+	model       string
+	thinking    string
+	prompts     int
+}
+
+func newHappyState() happyState {
+	return happyState{sessionFile: "/tmp/session-123.jsonl", provider: "anthropic", model: "claude-opus-4-5", thinking: "high"}
+}
+
+func happyModel(provider string, model string) map[string]any {
+	return map[string]any{
+		"id":            model,
+		"provider":      provider,
+		"reasoning":     true,
+		"contextWindow": 200000,
+		"maxTokens":     8192,
+	}
+}
+
+func happyForkMessages() []map[string]any {
+	return []map[string]any{
+		{"entryId": "entry-1", "text": "add a README"},
+		{"entryId": "entry-3", "text": "now add tests"},
+	}
+}
+
+func happyMessages() []map[string]any {
+	return []map[string]any{
+		{"role": "user", "content": "list the repo", "timestamp": 1700000000000},
+		{
+			"role": "assistant",
+			"content": []map[string]any{
+				{"type": "thinking", "thinking": "I should run ls."},
+				{"type": "text", "text": "Listing files."},
+				{"type": "toolCall", "id": "call-1", "name": "bash", "arguments": map[string]any{"command": "ls"}},
+			},
+			"stopReason": "toolUse",
+			"timestamp":  1700000001000,
+		},
+		{
+			"role":       "toolResult",
+			"toolCallId": "call-1",
+			"toolName":   "bash",
+			"content":    []map[string]any{{"type": "text", "text": "README.md\ngo.mod"}},
+			"isError":    false,
+			"timestamp":  1700000002000,
+		},
+		{
+			"role":       "assistant",
+			"content":    []map[string]any{{"type": "text", "text": "The repo has a README and go.mod."}},
+			"stopReason": "stop",
+			"timestamp":  1700000003000,
+		},
+	}
+}
+
+func handleHappyScenario(writer *bufio.Writer, state *happyState, requestID string, commandType string, command map[string]any) error {
 	switch commandType {
 	case commandGetState:
 		return writeResponse(writer, requestID, commandType, true, map[string]any{
 			"sessionId":             "session-123",
-			"sessionFile":           "/tmp/session-123.jsonl",
+			"sessionFile":           state.sessionFile,
 			"autoCompactionEnabled": true,
-			"model": map[string]any{
-				"id":            "claude-opus-4-5",
-				"provider":      "anthropic",
-				"contextWindow": 200000,
-				"maxTokens":     8192,
+			"thinkingLevel":         state.thinking,
+			"model":                 happyModel(state.provider, state.model),
+		}, "")
+	case commandSetModel:
+		provider, _ := command["provider"].(string)
+		model, _ := command["modelId"].(string)
+		if model == "missing-model" {
+			return writeResponse(writer, requestID, commandType, false, nil, "Model not found: "+provider+"/"+model)
+		}
+		state.provider = provider
+		state.model = model
+		return writeResponse(writer, requestID, commandType, true, happyModel(provider, model), "")
+	case commandCycleModel:
+		state.model = "claude-haiku-4-5"
+		return writeResponse(writer, requestID, commandType, true, map[string]any{
+			"model":         happyModel(state.provider, state.model),
+			"thinkingLevel": state.thinking,
+			"isScoped":      false,
+		}, "")
+	case commandGetAvailableModels:
+		return writeResponse(writer, requestID, commandType, true, map[string]any{
+			"models": []map[string]any{
+				happyModel("anthropic", "claude-opus-4-5"),
+				happyModel("anthropic", "claude-haiku-4-5"),
 			},
 		}, "")
+	case commandSetThinkingLevel:
+		level, _ := command["level"].(string)
+		state.thinking = level
+		return writeResponse(writer, requestID, commandType, true, nil, "")
+	case commandCycleThinkingLevel:
+		next := map[string]string{"off": "minimal", "minimal": "low", "low": "medium", "medium": "high", "high": "xhigh", "xhigh": "off"}
+		state.thinking = next[state.thinking]
+		return writeResponse(writer, requestID, commandType, true, map[string]any{"level": state.thinking}, "")
 	case commandNewSession:
 		parent, _ := command["parentSession"].(string)
 		cancelled := parent == "cancel-parent"
@@ -38,7 +127,45 @@ func handleHappyScenario(writer *bufio.Writer, requestID string, commandType str
 		}, ""); err != nil {
 			return err
 		}
+	case commandSwitchSession:
+		sessionPath, _ := command["sessionPath"].(string)
+		if sessionPath == "cancel-switch" {
+			return writeResponse(writer, requestID, commandType, true, map[string]any{"cancelled": true}, "")
+		}
+		state.sessionFile = sessionPath
+		return writeResponse(writer, requestID, commandType, true, map[string]any{"cancelled": false}, "")
+	case commandGetForkMessages:
+		return writeResponse(writer, requestID, commandType, true, map[string]any{"messages": happyForkMessages()}, "")
+	case commandFork:
+		entryID, _ := command["entryId"].(string)
+		for _, message := range happyForkMessages() {
+			if message["entryId"] == entryID {
+				return writeResponse(writer, requestID, commandType, true, map[string]any{"text": message["text"], "cancelled": false}, "")
+			}
+		}
+		return writeResponse(writer, requestID, commandType, false, nil, "Invalid entry ID for forking")
+	case commandGetMessages:
+		return writeResponse(writer, requestID, commandType, true, map[string]any{"messages": happyMessages()}, "")
+	case commandGetSessionStats:
+		return writeResponse(writer, requestID, commandType, true, map[string]any{
+			"sessionFile":       "/tmp/session-123.jsonl",
+			"sessionId":         "session-123",
+			"userMessages":      state.prompts,
+			"assistantMessages": state.prompts,
+			"toolCalls":         0,
+			"toolResults":       0,
+			"totalMessages":     2 * state.prompts,
+			"tokens": map[string]any{
+				"input":      10 * state.prompts,
+				"output":     5 * state.prompts,
+				"cacheRead":  0,
+				"cacheWrite": 0,
+				"total":      15 * state.prompts,
+			},
+			"cost": 0.25 * float64(state.prompts),
+		}, "")
 	case commandPrompt:
+		state.prompts++
 		if err := writeResponse(writer, requestID, commandType, true, nil, ""); err != nil {
 			return err
 		}
@@ -71,6 +198,23 @@ func handleHappyScenario(writer *bufio.Writer, requestID string, commandType str
 		}); err != nil {
 			return err
 		}
+	case commandBash:
+		shellCommand, _ := command["command"].(string)
+		if shellCommand == "make test" {
+			return writeResponse(writer, requestID, commandType, true, map[string]any{
+				"output":         "...last 2000 lines...",
+				"exitCode":       2,
+				"cancelled":      false,
+				"truncated":      true,
+				"fullOutputPath": "/tmp/pi-bash-1.log",
+			}, "")
+		}
+		return writeResponse(writer, requestID, commandType, true, map[string]any{
+			"output":    "On branch main\n",
+			"exitCode":  0,
+			"cancelled": false,
+			"truncated": false,
+		}, "")
 	case commandAbort:
 		return writeResponse(writer, requestID, commandType, true, nil, "")
 	default:
@@ -184,6 +328,241 @@ func maybeWriteAbortRunAgentEnd(writer *bufio.Writer, state *abortRunState) erro
 	})
 }
 
+type streamRunState struct {
+	abortSeen bool
+}
+
+func streamDeltaEvent(kind string, delta string) map[string]any {
+	return map[string]any{
+		"type":                  eventTypeMessageUpdate,
+		"message":               map[string]any{"role": "assistant", "content": []map[string]any{}},
+		"assistantMessageEvent": map[string]any{"type": kind, "contentIndex": 0, "delta": delta},
+	}
+}
+
+// handleStreamRunScenario streams a full tool turn, or for prompt "hold" one delta
+// and then waits for abort.
+func handleStreamRunScenario(writer *bufio.Writer, state *streamRunState, requestID string, commandType string, command map[string]any) error {
+	switch commandType {
+	case commandPrompt:
+		if err := writeResponse(writer, requestID, commandType, true, nil, ""); err != nil {
+			return err
+		}
+		if message, _ := command["message"].(string); message == "hold" {
+			return writeEvent(writer, streamDeltaEvent("text_delta", "partial"))
+		}
+		return writeEvents(writer,
+			map[string]any{"type": eventTypeAgentStart},
+			streamDeltaEvent("thinking_delta", "need "),
+			streamDeltaEvent("thinking_delta", "ls"),
+			map[string]any{"type": eventTypeMessageUpdate, "message": map[string]any{"role": "assistant"}, "assistantMessageEvent": map[string]any{"type": "toolcall_start", "contentIndex": 1}},
+			map[string]any{"type": eventTypeToolExecutionStart, "toolCallId": "call-1", "toolName": "bash", "args": map[string]any{"command": "ls"}},
+			map[string]any{"type": eventTypeToolExecutionEnd, "toolCallId": "call-1", "toolName": "bash", "result": map[string]any{"content": []map[string]any{{"type": "text", "text": "go.mod"}}}, "isError": false},
+			streamDeltaEvent("text_delta", "found "),
+			streamDeltaEvent("text_delta", "go.mod"),
+			assistantAgentEnd("found go.mod"),
+		)
+	case commandAbort:
+		state.abortSeen = true
+		if err := writeResponse(writer, requestID, commandType, true, nil, ""); err != nil {
+			return err
+		}
+		return writeEvent(writer, map[string]any{
+			"type":     eventTypeAgentEnd,
+			"messages": []map[string]any{{"role": "assistant", "content": "partial", "stopReason": "aborted"}},
+		})
+	case commandGetState:
+		sessionID := "no-abort"
+		if state.abortSeen {
+			sessionID = "abort-observed"
+		}
+		return writeResponse(writer, requestID, commandType, true, map[string]any{
+			"sessionId": sessionID,
+			"model":     happyModel("anthropic", "claude-opus-4-5"),
+		}, "")
+	default:
+		return writeResponse(writer, requestID, commandType, true, map[string]any{}, "")
+	}
+}
+
+// handleStructuredOutputScenario answers the first prompt with prose-wrapped JSON
+// missing a required field, then a fenced valid value once the repair prompt
+// (sent as a follow-up) names it.
+func handleStructuredOutputScenario(writer *bufio.Writer, requestID string, commandType string, command map[string]any) error {
+	switch commandType {
+	case commandPrompt:
+		message, _ := command["message"].(string)
+		repair := strings.Contains(message, `missing required property "stars"`)
+		if behavior, _ := command["streamingBehavior"].(string); repair && behavior != "followUp" {
+			return writeResponse(writer, requestID, commandType, false, nil, "repair must be sent as a follow-up")
+		}
+		if err := writeResponse(writer, requestID, commandType, true, nil, ""); err != nil {
+			return err
+		}
+		text := `Sure! Here it is: {"name":"pi"} Let me know if you need more.`
+		if repair {
+			text = "```json\n{\"name\":\"pi\",\"stars\":5}\n```"
+		}
+		return writeEvent(writer, assistantAgentEnd(text))
+	default:
+		return writeResponse(writer, requestID, commandType, true, map[string]any{}, "")
+	}
+}
+
+// handleSupervisedCrashScenario reports the --session it was started with (or
+// switched to since), so tests can tell a resumed process from a fresh one.
+// Prompt "crash" exits.
+func handleSupervisedCrashScenario(writer *bufio.Writer, switched *string, sessionArgs []string, requestID string, commandType string, command map[string]any) error {
+	sessionFile := "/sessions/original.jsonl"
+	if len(sessionArgs) > 0 {
+		sessionFile = sessionArgs[0]
+	}
+	if *switched != "" {
+		sessionFile = *switched
+	}
+	switch commandType {
+	case commandSwitchSession:
+		*switched, _ = command["sessionPath"].(string)
+		return writeResponse(writer, requestID, commandType, true, map[string]any{"cancelled": false}, "")
+	case commandFork:
+		*switched = "/sessions/forked.jsonl"
+		return writeResponse(writer, requestID, commandType, true, map[string]any{"text": "", "cancelled": false}, "")
+	case commandPrompt:
+		if err := writeResponse(writer, requestID, commandType, true, nil, ""); err != nil {
+			return err
+		}
+		return writeEvent(writer, assistantAgentEnd(fmt.Sprintf("session=%s resumed=%t", sessionFile, len(sessionArgs) > 0)))
+	case commandGetState:
+		return writeResponse(writer, requestID, commandType, true, map[string]any{
+			"sessionId":   "supervised",
+			"model":       happyModel("openai", "gpt-5"),
+			"sessionFile": sessionFile,
+		}, "")
+	default:
+		return writeResponse(writer, requestID, commandType, true, map[string]any{}, "")
+	}
+}
+
+// handleStderrChatterScenario writes 20 numbered lines to
+// stderr before answering get_state.
+func handleStderrChatterScenario(writer *bufio.Writer, stderr io.Writer, requestID string, commandType string) error {
+	if commandType == commandGetState {
+		for line := 1; line <= 20; line++ {
+			if _, err := fmt.Fprintf(stderr, "stderr line %02d\n", line); err != nil {
+				return err
+			}
+		}
+		return writeResponse(writer, requestID, commandType, true, map[string]any{
+			"sessionId": "stderr",
+			"model":     happyModel("openai", "gpt-5"),
+		}, "")
+	}
+	return writeResponse(writer, requestID, commandType, true, map[string]any{}, "")
+}
+
+// handleStderrLongLineScenario writes one 150-byte stderr line, then a short
+// one, before answering get_state.
+func handleStderrLongLineScenario(writer *bufio.Writer, stderr io.Writer, requestID string, commandType string) error {
+	if commandType == commandGetState {
+		if _, err := fmt.Fprintf(stderr, "%s\nstderr done\n", strings.Repeat("x", 150)); err != nil {
+			return err
+		}
+		return writeResponse(writer, requestID, commandType, true, map[string]any{
+			"sessionId": "stderr",
+			"model":     happyModel("openai", "gpt-5"),
+		}, "")
+	}
+	return writeResponse(writer, requestID, commandType, true, map[string]any{}, "")
+}
+
+func budgetUsage(input int, output int, cost float64) map[string]any {
+	return map[string]any{"input": input, "output": output, "cacheRead": 0, "cacheWrite": 0, "cost": map[string]any{"total": cost}}
+}
+
+// handleBudgetRunScenario runs a tool loop that never finishes on its own
+// ("loop") or goes silent after agent_start ("stall"); both end only on abort.
+func handleBudgetRunScenario(writer *bufio.Writer, requestID string, commandType string, command map[string]any) error {
+	switch commandType {
+	case commandPrompt:
+		if err := writeResponse(writer, requestID, commandType, true, nil, ""); err != nil {
+			return err
+		}
+		if message, _ := command["message"].(string); message == "stall" {
+			return writeEvent(writer, map[string]any{"type": eventTypeAgentStart})
+		}
+		assistant := func(usage map[string]any) map[string]any {
+			return map[string]any{"role": "assistant", "content": []map[string]any{}, "usage": usage}
+		}
+		return writeEvents(writer,
+			map[string]any{"type": eventTypeAgentStart},
+			map[string]any{"type": eventTypeTurnStart},
+			map[string]any{"type": eventTypeMessageUpdate, "message": assistant(budgetUsage(100, 300, 0.01)), "assistantMessageEvent": map[string]any{"type": "text_delta", "delta": "working"}},
+			map[string]any{"type": eventTypeToolExecutionStart, "toolCallId": "call-1", "toolName": "bash", "args": map[string]any{"command": "ls"}},
+			map[string]any{"type": eventTypeToolExecutionEnd, "toolCallId": "call-1", "toolName": "bash", "result": map[string]any{"content": []any{}}, "isError": false},
+			map[string]any{"type": eventTypeTurnEnd, "message": assistant(budgetUsage(100, 600, 0.02)), "toolResults": []any{}},
+			map[string]any{"type": eventTypeTurnStart},
+			map[string]any{"type": eventTypeMessageUpdate, "message": assistant(budgetUsage(700, 500, 0.03)), "assistantMessageEvent": map[string]any{"type": "text_delta", "delta": "still working"}},
+			map[string]any{"type": eventTypeToolExecutionStart, "toolCallId": "call-2", "toolName": "bash", "args": map[string]any{"command": "ls"}},
+		)
+	case commandAbort:
+		if err := writeResponse(writer, requestID, commandType, true, nil, ""); err != nil {
+			return err
+		}
+		return writeEvent(writer, map[string]any{
+			"type": eventTypeAgentEnd,
+			"messages": []map[string]any{
+				{"role": "assistant", "content": []map[string]any{{"type": "text", "text": "stopped"}}, "stopReason": "aborted"},
+			},
+		})
+	default:
+		return writeResponse(writer, requestID, commandType, true, map[string]any{}, "")
+	}
+}
+
+// handleRunHangScenario starts a run that never ends: abort is acknowledged
+// but no agent_end follows.
+func handleRunHangScenario(writer *bufio.Writer, requestID string, commandType string) error {
+	if err := writeResponse(writer, requestID, commandType, true, map[string]any{}, ""); err != nil {
+		return err
+	}
+	if commandType == commandPrompt {
+		return writeEvent(writer, map[string]any{"type": eventTypeAgentStart})
+	}
+	return nil
+}
+
+type bashAbortState struct {
+	pendingBashID string
+	abortSeen     bool
+}
+
+func handleBashAbortScenario(writer *bufio.Writer, state *bashAbortState, requestID string, commandType string) error {
+	switch commandType {
+	case commandBash:
+		state.pendingBashID = requestID
+		return nil
+	case commandAbortBash:
+		state.abortSeen = true
+		if state.pendingBashID != "" {
+			if err := writeResponse(writer, state.pendingBashID, commandBash, true, map[string]any{"output": "", "cancelled": true, "truncated": false}, ""); err != nil {
+				return err
+			}
+			state.pendingBashID = ""
+		}
+		return writeResponse(writer, requestID, commandType, true, nil, "")
+	case commandGetState:
+		if !state.abortSeen {
+			return writeResponse(writer, requestID, commandType, false, nil, "abort_bash not called")
+		}
+		return writeResponse(writer, requestID, commandType, true, map[string]any{
+			"sessionId": "abort-bash-observed",
+			"model":     happyModel("anthropic", "claude-opus-4-5"),
+		}, "")
+	default:
+		return writeResponse(writer, requestID, commandType, true, map[string]any{}, "")
+	}
+}
+
 type runCancelAbortState struct {
 	promptSeen bool
 	abortSeen  bool
@@ -248,6 +627,303 @@ func handleRunDetailedSignalsScenario(writer *bufio.Writer, requestID string, co
 	}
 }
 
+// handleRunRecoveryTimelineScenario emits two compactions around a two-attempt
+// retry sequence before completing.
+func handleRunRecoveryTimelineScenario(writer *bufio.Writer, requestID string, commandType string) error {
+	switch commandType {
+	case commandPrompt:
+		if err := writeResponse(writer, requestID, commandType, true, nil, ""); err != nil {
+			return err
+		}
+		compacted := map[string]any{"summary": "compacted", "firstKeptEntryId": "entry-1", "tokensBefore": 120000}
+		return writeEvents(writer,
+			map[string]any{"type": eventTypeAutoCompactionStart, "reason": "overflow"},
+			map[string]any{"type": eventTypeAutoCompactionEnd, "result": compacted, "aborted": false, "willRetry": true},
+			map[string]any{"type": eventTypeAutoRetryStart, "attempt": 1, "maxAttempts": 3, "delayMs": 10, "errorMessage": "overloaded"},
+			map[string]any{"type": eventTypeAutoRetryStart, "attempt": 2, "maxAttempts": 3, "delayMs": 20, "errorMessage": "overloaded"},
+			map[string]any{"type": eventTypeAutoRetryEnd, "success": true, "attempt": 2},
+			map[string]any{"type": eventTypeAutoCompactionStart, "reason": "threshold"},
+			map[string]any{"type": eventTypeAutoCompactionEnd, "result": compacted, "aborted": false, "willRetry": false},
+			assistantAgentEnd("recovered twice"),
+		)
+	default:
+		return writeResponse(writer, requestID, commandType, true, map[string]any{}, "")
+	}
+}
+
+// handleRunTimingScenario spaces run milestones 20ms apart: first text delta,
+// tool call, retry (announcing a 30ms delay), final assistant message.
+func handleRunTimingScenario(writer *bufio.Writer, requestID string, commandType string) error {
+	if commandType != commandPrompt {
+		return writeResponse(writer, requestID, commandType, true, map[string]any{}, "")
+	}
+	usage := map[string]any{"input": 10, "output": 40, "cacheRead": 0, "cacheWrite": 0}
+	final := map[string]any{"role": "assistant", "content": []map[string]any{{"type": "text", "text": "timed"}}, "usage": usage}
+	steps := [][]map[string]any{
+		{{"type": eventTypeAgentStart}, {"type": eventTypeMessageStart, "message": map[string]any{"role": "assistant", "content": []any{}}}},
+		{{"type": eventTypeMessageUpdate, "message": map[string]any{"role": "assistant", "content": []any{}}, "assistantMessageEvent": map[string]any{"type": "text_delta", "delta": "ti"}}},
+		{{"type": eventTypeToolExecutionStart, "toolCallId": "call-1", "toolName": "bash", "args": map[string]any{}}},
+		{{"type": eventTypeToolExecutionEnd, "toolCallId": "call-1", "toolName": "bash", "result": map[string]any{}, "isError": false},
+			{"type": eventTypeAutoRetryStart, "attempt": 1, "maxAttempts": 3, "delayMs": 30, "errorMessage": "overloaded"}},
+		{{"type": eventTypeAutoRetryEnd, "success": true, "attempt": 1}, {"type": eventTypeMessageStart, "message": map[string]any{"role": "assistant", "content": []any{}}}},
+		{{"type": eventTypeMessageEnd, "message": final}, {"type": eventTypeAgentEnd, "messages": []map[string]any{final}}},
+	}
+	if err := writeResponse(writer, requestID, commandType, true, nil, ""); err != nil {
+		return err
+	}
+	for _, step := range steps {
+		time.Sleep(20 * time.Millisecond)
+		if err := writeEvents(writer, step...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func handleRunToolCallsScenario(writer *bufio.Writer, requestID string, commandType string) error {
+	switch commandType {
+	case commandPrompt:
+		if err := writeResponse(writer, requestID, commandType, true, nil, ""); err != nil {
+			return err
+		}
+		toolTurn := map[string]any{
+			"role": "assistant",
+			"content": []map[string]any{
+				{"type": "toolCall", "id": "call-1", "name": "bash", "arguments": map[string]any{"command": "ls"}},
+				{"type": "toolCall", "id": "call-2", "name": "read", "arguments": map[string]any{"path": "missing.txt"}},
+			},
+			"stopReason": "toolUse",
+			"usage":      map[string]any{"input": 100, "output": 20, "cacheRead": 0, "cacheWrite": 0},
+		}
+		toolResults := []map[string]any{
+			{"role": "toolResult", "toolCallId": "call-1", "toolName": "bash", "content": []map[string]any{{"type": "text", "text": "go.mod\nREADME.md"}}, "isError": false},
+			{"role": "toolResult", "toolCallId": "call-2", "toolName": "read", "content": []map[string]any{{"type": "text", "text": "ENOENT"}}, "isError": true},
+		}
+		finalTurn := map[string]any{
+			"role":       "assistant",
+			"content":    []map[string]any{{"type": "text", "text": "listed"}},
+			"stopReason": "stop",
+			"usage":      map[string]any{"input": 150, "output": 10, "cacheRead": 0, "cacheWrite": 0},
+		}
+
+		if err := writeEvents(writer,
+			map[string]any{"type": eventTypeAgentStart},
+			map[string]any{"type": eventTypeTurnStart},
+			map[string]any{"type": eventTypeMessageStart, "message": toolTurn},
+			map[string]any{"type": eventTypeMessageEnd, "message": toolTurn},
+			map[string]any{"type": eventTypeToolExecutionStart, "toolCallId": "call-1", "toolName": "bash", "args": map[string]any{"command": "ls"}},
+			map[string]any{"type": eventTypeToolExecutionUpdate, "toolCallId": "call-1", "toolName": "bash", "args": map[string]any{"command": "ls"}, "partialResult": map[string]any{"content": []map[string]any{{"type": "text", "text": "go.mod"}}}},
+			map[string]any{"type": eventTypeToolExecutionStart, "toolCallId": "call-2", "toolName": "read", "args": map[string]any{"path": "missing.txt"}},
+		); err != nil {
+			return err
+		}
+		time.Sleep(20 * time.Millisecond)
+		return writeEvents(writer,
+			map[string]any{"type": eventTypeToolExecutionEnd, "toolCallId": "call-2", "toolName": "read", "result": map[string]any{"content": []map[string]any{{"type": "text", "text": "ENOENT"}}}, "isError": true},
+			map[string]any{"type": eventTypeToolExecutionEnd, "toolCallId": "call-1", "toolName": "bash", "result": map[string]any{"content": []map[string]any{{"type": "text", "text": "go.mod\nREADME.md"}}}, "isError": false},
+			map[string]any{"type": eventTypeTurnEnd, "message": toolTurn, "toolResults": toolResults},
+			map[string]any{"type": eventTypeTurnStart},
+			map[string]any{"type": eventTypeMessageStart, "message": finalTurn},
+			map[string]any{"type": eventTypeMessageEnd, "message": finalTurn},
+			map[string]any{"type": eventTypeTurnEnd, "message": finalTurn, "toolResults": []map[string]any{}},
+			map[string]any{"type": eventTypeAgentEnd, "messages": []map[string]any{toolTurn, toolResults[0], toolResults[1], finalTurn}},
+		)
+	default:
+		return writeResponse(writer, requestID, commandType, true, map[string]any{}, "")
+	}
+}
+
+func handleExtensionUIScenario(writer *bufio.Writer, method string, requestID string, commandType string, command map[string]any) error {
+	switch commandType {
+	case commandPrompt:
+		if err := writeResponse(writer, requestID, commandType, true, nil, ""); err != nil {
+			return err
+		}
+		if method == "notify" {
+			return writeEvents(writer,
+				map[string]any{"type": eventTypeExtensionUIRequest, "id": "ui-1", "method": "notify", "message": "indexing", "notifyType": "info"},
+				map[string]any{"type": eventTypeExtensionUIRequest, "id": "ui-2", "method": "setStatus", "statusKey": "indexer", "statusText": "3 files"},
+				assistantAgentEnd("notified"),
+			)
+		}
+		if method == "status_burst" {
+			for index := 1; index <= 20; index++ {
+				status := map[string]any{"type": eventTypeExtensionUIRequest, "id": fmt.Sprintf("ui-%d", index), "method": "setStatus", "statusKey": "indexer", "statusText": fmt.Sprintf("%d files", index)}
+				if err := writeEvent(writer, status); err != nil {
+					return err
+				}
+			}
+			return writeEvent(writer, assistantAgentEnd("notified"))
+		}
+		request := map[string]any{"type": eventTypeExtensionUIRequest, "id": "ui-1", "method": method, "title": "Extension asks"}
+		switch method {
+		case "select":
+			request["options"] = []string{"alpha", "beta"}
+		case "confirm":
+			request["message"] = "Proceed?"
+		case "input":
+			request["placeholder"] = "name"
+		case "editor":
+			request["prefill"] = "draft"
+		}
+		return writeEvent(writer, request)
+	case commandExtensionUIResponse:
+		if id, _ := command["id"].(string); id != "ui-1" {
+			return fmt.Errorf("unexpected extension ui response id %q", id)
+		}
+		answer := fmt.Sprintf("value:%v", command["value"])
+		if cancelled, _ := command["cancelled"].(bool); cancelled {
+			answer = "cancelled"
+		} else if confirmed, ok := command["confirmed"].(bool); ok {
+			answer = fmt.Sprintf("confirmed:%t", confirmed)
+		}
+		return writeEvent(writer, assistantAgentEnd(answer))
+	default:
+		return writeResponse(writer, requestID, commandType, true, map[string]any{}, "")
+	}
+}
+
+type editorBridgeState struct {
+	replies []string
+}
+
+func handleCustomToolsScenario(writer *bufio.Writer, state *editorBridgeState, requestID string, commandType string, command map[string]any) error {
+	switch commandType {
+	case commandPrompt:
+		names, err := readBridgedToolNames()
+		if err != nil {
+			return writeResponse(writer, requestID, commandType, false, nil, err.Error())
+		}
+		if err := writeResponse(writer, requestID, commandType, true, nil, ""); err != nil {
+			return err
+		}
+		return writeToolBridgeCall(writer, "ui-1", "call-1", names[0], map[string]any{"id": "T-1"})
+	case commandExtensionUIResponse:
+		value, _ := command["value"].(string)
+		state.replies = append(state.replies, value)
+		if len(state.replies) == 1 {
+			return writeToolBridgeCall(writer, "ui-2", "call-2", "broken_tool", map[string]any{})
+		}
+		return writeEvent(writer, assistantAgentEnd(strings.Join(state.replies, "\n")))
+	default:
+		return writeResponse(writer, requestID, commandType, true, map[string]any{}, "")
+	}
+}
+
+// handleCustomToolsCancelScenario plays the generated extension seeing pi abort
+// a bridged tool call while Go is still executing it.
+func handleCustomToolsCancelScenario(writer *bufio.Writer, requestID string, commandType string, command map[string]any) error {
+	switch commandType {
+	case commandPrompt:
+		names, err := readBridgedToolNames()
+		if err != nil {
+			return writeResponse(writer, requestID, commandType, false, nil, err.Error())
+		}
+		if err := writeResponse(writer, requestID, commandType, true, nil, ""); err != nil {
+			return err
+		}
+		if err := writeToolBridgeCall(writer, "ui-1", "call-1", names[0], map[string]any{"id": "T-1"}); err != nil {
+			return err
+		}
+		return writeEvent(writer, map[string]any{
+			"type":       eventTypeExtensionUIRequest,
+			"id":         "ui-2",
+			"method":     "setStatus",
+			"statusKey":  toolBridgeCancelKey,
+			"statusText": "call-1",
+		})
+	case commandExtensionUIResponse:
+		value, _ := command["value"].(string)
+		return writeEvent(writer, assistantAgentEnd(value))
+	default:
+		return writeResponse(writer, requestID, commandType, true, map[string]any{}, "")
+	}
+}
+
+func handleApprovalGateScenario(writer *bufio.Writer, state *editorBridgeState, requestID string, commandType string, command map[string]any) error {
+	switch commandType {
+	case commandPrompt:
+		if err := writeResponse(writer, requestID, commandType, true, nil, ""); err != nil {
+			return err
+		}
+		return writeApprovalRequest(writer, "ui-1", "call-1", "bash", map[string]any{"command": "rm -rf build"})
+	case commandExtensionUIResponse:
+		value, _ := command["value"].(string)
+		state.replies = append(state.replies, value)
+		if len(state.replies) == 1 {
+			return writeApprovalRequest(writer, "ui-2", "call-2", "read", map[string]any{"path": "README.md"})
+		}
+		return writeEvent(writer, assistantAgentEnd(strings.Join(state.replies, "\n")))
+	default:
+		return writeResponse(writer, requestID, commandType, true, map[string]any{}, "")
+	}
+}
+
+func writeApprovalRequest(writer *bufio.Writer, uiID string, toolCallID string, toolName string, input map[string]any) error {
+	request, err := json.Marshal(map[string]any{"toolCallId": toolCallID, "toolName": toolName, "input": input})
+	if err != nil {
+		return err
+	}
+	return writeEvent(writer, map[string]any{
+		"type":    eventTypeExtensionUIRequest,
+		"id":      uiID,
+		"method":  "editor",
+		"title":   approvalGateUITitle,
+		"prefill": string(request),
+	})
+}
+
+func readBridgedToolNames() ([]string, error) {
+	path := os.Getenv(toolBridgeFileEnv)
+	if path == "" {
+		return nil, fmt.Errorf("%s not set", toolBridgeFileEnv)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var tools []struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(content, &tools); err != nil {
+		return nil, err
+	}
+	if len(tools) == 0 {
+		return nil, fmt.Errorf("no tools declared")
+	}
+	names := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		names = append(names, tool.Name)
+	}
+	return names, nil
+}
+
+func writeToolBridgeCall(writer *bufio.Writer, uiID string, toolCallID string, name string, params map[string]any) error {
+	call, err := json.Marshal(map[string]any{"toolCallId": toolCallID, "name": name, "params": params})
+	if err != nil {
+		return err
+	}
+	return writeEvent(writer, map[string]any{
+		"type":    eventTypeExtensionUIRequest,
+		"id":      uiID,
+		"method":  "editor",
+		"title":   toolBridgeUITitle,
+		"prefill": string(call),
+	})
+}
+
+func assistantAgentEnd(text string) map[string]any {
+	return map[string]any{
+		"type": eventTypeAgentEnd,
+		"messages": []map[string]any{
+			{
+				"role":    "assistant",
+				"content": []map[string]any{{"type": "text", "text": text}},
+			},
+		},
+	}
+}
+
 func writeResponse(writer *bufio.Writer, id string, command string, success bool, data any, errText string) error {
 	payload := map[string]any{
 		"type":    eventTypeResponse,
@@ -274,3 +950,12 @@ func writeEvent(writer *bufio.Writer, payload map[string]any) error {
 	}
 	return writer.Flush()
 }
+
+func writeEvents(writer *bufio.Writer, payloads ...map[string]any) error {
+	for _, payload := range payloads {
+		if err := writeEvent(writer, payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
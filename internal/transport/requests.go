@@ -26,6 +26,11 @@ func (manager *RequestManager) Resolve(response rpc.Response) bool {
 	return manager.registry.Resolve(response.ID, response)
 }
 
+// LatestPending returns the most recently sent request still awaiting a response.
+func (manager *RequestManager) LatestPending() string {
+	return manager.registry.Latest()
+}
+
 func (manager *RequestManager) CurrentError() error {
 	return manager.registry.CurrentError()
 }
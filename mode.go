@@ -40,6 +40,11 @@ const (
 
 type SkillsOptions = sdk.SkillsOptions
 
+type UIHandler = sdk.UIHandler
+type ToolDefinition = sdk.ToolDefinition
+type ToolResult = sdk.ToolResult
+type ApproveToolCallFunc = sdk.ApproveToolCallFunc
+
 type SessionOptions = sdk.SessionOptions
 type OneShotOptions = sdk.OneShotOptions
 
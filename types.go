@@ -3,15 +3,28 @@ package pi
 import "github.com/joshp123/pi-golang/internal/sdk"
 
 type Event = sdk.Event
+type StderrLine = sdk.StderrLine
+type TypedEvent = sdk.TypedEvent
 
 const (
+	EventTypeAgentStart          = sdk.EventTypeAgentStart
 	EventTypeAgentEnd            = sdk.EventTypeAgentEnd
+	EventTypeTurnStart           = sdk.EventTypeTurnStart
+	EventTypeTurnEnd             = sdk.EventTypeTurnEnd
+	EventTypeMessageStart        = sdk.EventTypeMessageStart
 	EventTypeMessageUpdate       = sdk.EventTypeMessageUpdate
+	EventTypeMessageEnd          = sdk.EventTypeMessageEnd
 	EventTypeAutoCompactionStart = sdk.EventTypeAutoCompactionStart
 	EventTypeAutoCompactionEnd   = sdk.EventTypeAutoCompactionEnd
 	EventTypeAutoRetryStart      = sdk.EventTypeAutoRetryStart
 	EventTypeAutoRetryEnd        = sdk.EventTypeAutoRetryEnd
+	EventTypeToolExecutionStart  = sdk.EventTypeToolExecutionStart
+	EventTypeToolExecutionUpdate = sdk.EventTypeToolExecutionUpdate
+	EventTypeToolExecutionEnd    = sdk.EventTypeToolExecutionEnd
+	EventTypeExtensionUIRequest  = sdk.EventTypeExtensionUIRequest
+	EventTypeToolCallDenied      = sdk.EventTypeToolCallDenied
 	EventTypeProcessDied         = sdk.EventTypeProcessDied
+	EventTypeProcessRestarted    = sdk.EventTypeProcessRestarted
 	EventTypeSubscriptionDrop    = sdk.EventTypeSubscriptionDrop
 )
 
@@ -54,6 +67,24 @@ type TerminalReason = sdk.TerminalReason
 
 type TerminalOutcome = sdk.TerminalOutcome
 type RunDetailedResult = sdk.RunDetailedResult
+type ToolExecution = sdk.ToolExecution
+type TurnSummary = sdk.TurnSummary
+type CompactionEpisode = sdk.CompactionEpisode
+type RetryAttempt = sdk.RetryAttempt
+type RunTiming = sdk.RunTiming
+type RunOptions = sdk.RunOptions
+type Budget = sdk.Budget
+type BudgetUsage = sdk.BudgetUsage
+type BudgetLimit = sdk.BudgetLimit
+
+const (
+	BudgetLimitInputTokens  = sdk.BudgetLimitInputTokens
+	BudgetLimitOutputTokens = sdk.BudgetLimitOutputTokens
+	BudgetLimitCost         = sdk.BudgetLimitCost
+	BudgetLimitTurns        = sdk.BudgetLimitTurns
+	BudgetLimitToolCalls    = sdk.BudgetLimitToolCalls
+	BudgetLimitWallTime     = sdk.BudgetLimitWallTime
+)
 
 type CompletionClass = sdk.CompletionClass
 
@@ -75,8 +106,46 @@ const (
 	BrokenCauseClient      = sdk.BrokenCauseClient
 )
 
+type RunQueueStats = sdk.RunQueueStats
 type ShareResult = sdk.ShareResult
+type BashResult = sdk.BashResult
+type SessionStats = sdk.SessionStats
+type ForkResult = sdk.ForkResult
+type ForkMessage = sdk.ForkMessage
+type ThinkingLevel = sdk.ThinkingLevel
+
+const (
+	ThinkingLevelOff     = sdk.ThinkingLevelOff
+	ThinkingLevelMinimal = sdk.ThinkingLevelMinimal
+	ThinkingLevelLow     = sdk.ThinkingLevelLow
+	ThinkingLevelMedium  = sdk.ThinkingLevelMedium
+	ThinkingLevelHigh    = sdk.ThinkingLevelHigh
+	ThinkingLevelXHigh   = sdk.ThinkingLevelXHigh
+)
+
+type DecisionAction = sdk.DecisionAction
+
+const (
+	DecisionAllow = sdk.DecisionAllow
+	DecisionDeny  = sdk.DecisionDeny
+)
+
+type ToolCallRequest = sdk.ToolCallRequest
+type Decision = sdk.Decision
+
+type UIMethod = sdk.UIMethod
+
+const (
+	UIMethodSelect    = sdk.UIMethodSelect
+	UIMethodConfirm   = sdk.UIMethodConfirm
+	UIMethodInput     = sdk.UIMethodInput
+	UIMethodEditor    = sdk.UIMethodEditor
+	UIMethodNotify    = sdk.UIMethodNotify
+	UIMethodSetStatus = sdk.UIMethodSetStatus
+)
+
 type ModelInfo = sdk.ModelInfo
+type ModelCycleResult = sdk.ModelCycleResult
 type SessionState = sdk.SessionState
 type CompactResult = sdk.CompactResult
 
@@ -91,11 +160,58 @@ const (
 
 type LoadedSkill = sdk.LoadedSkill
 
+type StreamChunk = sdk.StreamChunk
+type StreamChunkKind = sdk.StreamChunkKind
+
+const (
+	StreamChunkTextDelta     = sdk.StreamChunkTextDelta
+	StreamChunkThinkingDelta = sdk.StreamChunkThinkingDelta
+	StreamChunkToolStart     = sdk.StreamChunkToolStart
+	StreamChunkToolEnd       = sdk.StreamChunkToolEnd
+	StreamChunkOutcome       = sdk.StreamChunkOutcome
+)
+
 type AgentMessage = sdk.AgentMessage
+type ContentBlock = sdk.ContentBlock
+type TextBlock = sdk.TextBlock
+type ThinkingBlock = sdk.ThinkingBlock
+type ImageBlock = sdk.ImageBlock
+type ToolCallBlock = sdk.ToolCallBlock
+type UnknownBlock = sdk.UnknownBlock
+
+const (
+	ContentBlockText     = sdk.ContentBlockText
+	ContentBlockThinking = sdk.ContentBlockThinking
+	ContentBlockImage    = sdk.ContentBlockImage
+	ContentBlockToolCall = sdk.ContentBlockToolCall
+)
+
+type Transcript = sdk.Transcript
+type TranscriptEntry = sdk.TranscriptEntry
+
+func NewTranscript(messages []AgentMessage) (Transcript, error) {
+	return sdk.NewTranscript(messages)
+}
+
+type AgentStartEvent = sdk.AgentStartEvent
 type AgentEndEvent = sdk.AgentEndEvent
+type TurnStartEvent = sdk.TurnStartEvent
+type TurnEndEvent = sdk.TurnEndEvent
+type MessageStartEvent = sdk.MessageStartEvent
+type MessageEndEvent = sdk.MessageEndEvent
 type AssistantMessageDelta = sdk.AssistantMessageDelta
 type MessageUpdateEvent = sdk.MessageUpdateEvent
 type AutoCompactionStartEvent = sdk.AutoCompactionStartEvent
 type AutoCompactionEndEvent = sdk.AutoCompactionEndEvent
 type AutoRetryStartEvent = sdk.AutoRetryStartEvent
 type AutoRetryEndEvent = sdk.AutoRetryEndEvent
+type ToolExecutionStartEvent = sdk.ToolExecutionStartEvent
+type ToolExecutionUpdateEvent = sdk.ToolExecutionUpdateEvent
+type ToolExecutionEndEvent = sdk.ToolExecutionEndEvent
+type ExtensionUIRequest = sdk.ExtensionUIRequest
+type ToolCallDeniedEvent = sdk.ToolCallDeniedEvent
+type ProcessDiedEvent = sdk.ProcessDiedEvent
+type ProcessRestartedEvent = sdk.ProcessRestartedEvent
+type SubscriptionDropEvent = sdk.SubscriptionDropEvent
+type UnknownEvent = sdk.UnknownEvent
+type InvalidEvent = sdk.InvalidEvent